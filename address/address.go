@@ -0,0 +1,234 @@
+// Package address derives Bitcoin receive and change addresses from a
+// mnemonic following BIP44 (P2PKH), BIP49 (P2SH-P2WPKH), BIP84 (P2WPKH) and
+// BIP86 (P2TR).
+package address
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/base58"
+	"github.com/decen-one/go-bip39/bech32"
+	"github.com/decen-one/go-bip39/bip32"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Network holds the address encoding parameters of a Bitcoin network.
+type Network struct {
+	Name             string
+	PubKeyHashPrefix byte
+	ScriptHashPrefix byte
+	HRP              string
+
+	// CoinType is the BIP44 coin type of the network.
+	CoinType uint32
+
+	// HDPrivateVersion is the version of serialized extended private keys.
+	HDPrivateVersion uint32
+}
+
+var (
+	// Mainnet is the Bitcoin main network.
+	Mainnet = &Network{Name: "mainnet", PubKeyHashPrefix: 0x00, ScriptHashPrefix: 0x05, HRP: "bc", CoinType: 0, HDPrivateVersion: bip32.MainnetPrivateVersion}
+
+	// Testnet is the Bitcoin test network.
+	Testnet = &Network{Name: "testnet", PubKeyHashPrefix: 0x6f, ScriptHashPrefix: 0xc4, HRP: "tb", CoinType: 1, HDPrivateVersion: bip32.TestnetPrivateVersion}
+
+	// Regtest is the Bitcoin regression test network.
+	Regtest = &Network{Name: "regtest", PubKeyHashPrefix: 0x6f, ScriptHashPrefix: 0xc4, HRP: "bcrt", CoinType: 1, HDPrivateVersion: bip32.TestnetPrivateVersion}
+)
+
+// Type is an address type together with the BIP that defines its derivation
+// path.
+type Type int
+
+const (
+	// P2PKH is a legacy pay to public key hash address derived at
+	// m/44'/coin'/account'/change/index.
+	P2PKH Type = iota
+
+	// P2SHP2WPKH is a nested segwit address derived at
+	// m/49'/coin'/account'/change/index.
+	P2SHP2WPKH
+
+	// P2WPKH is a native segwit address derived at
+	// m/84'/coin'/account'/change/index.
+	P2WPKH
+
+	// P2TR is a key path only taproot address derived at
+	// m/86'/coin'/account'/change/index.
+	P2TR
+)
+
+var (
+	// ErrInvalidType is returned when trying to use an unknown address type.
+	ErrInvalidType = errors.New("Invalid address type")
+
+	// ErrInvalidPublicKey is returned when trying to encode a malformed public
+	// key.
+	ErrInvalidPublicKey = errors.New("Invalid public key")
+)
+
+// purposes maps address types to their BIP43 purpose.
+var purposes = map[Type]uint32{
+	P2PKH:      44,
+	P2SHP2WPKH: 49,
+	P2WPKH:     84,
+	P2TR:       86,
+}
+
+// Purpose returns the BIP43 purpose of the address type.
+func (t Type) Purpose() uint32 {
+	return purposes[t]
+}
+
+// AccountPath returns the account level derivation path of the address type
+// on the given network, such as "m/84'/0'/0'".
+func AccountPath(t Type, net *Network, account uint32) string {
+	return fmt.Sprintf("m/%d'/%d'/%d'", t.Purpose(), net.CoinType, account)
+}
+
+// Path returns the full derivation path of an address, such as
+// "m/84'/0'/0'/0/0".
+func Path(t Type, net *Network, account uint32, change bool, index uint32) string {
+	chain := 0
+	if change {
+		chain = 1
+	}
+
+	return fmt.Sprintf("%s/%d/%d", AccountPath(t, net, account), chain, index)
+}
+
+// Wallet derives addresses from the BIP32 root key of a mnemonic.
+type Wallet struct {
+	root *bip32.Key
+	net  *Network
+}
+
+// NewWallet creates a wallet on the given network from a mnemonic and
+// passphrase.
+func NewWallet(lang string, mnemonic string, passphrase string, net *Network) (*Wallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(lang, mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	root.Version = net.HDPrivateVersion
+
+	return &Wallet{root: root, net: net}, nil
+}
+
+// RootKey returns the BIP32 root key of the wallet.
+func (w *Wallet) RootKey() *bip32.Key {
+	return w.root
+}
+
+// Network returns the network of the wallet.
+func (w *Wallet) Network() *Network {
+	return w.net
+}
+
+// Address returns the address of the given type at the given account, chain
+// and index.
+func (w *Wallet) Address(t Type, account uint32, change bool, index uint32) (string, error) {
+	if _, ok := purposes[t]; !ok {
+		return "", ErrInvalidType
+	}
+
+	key, err := w.root.Derive(Path(t, w.net, account, change, index))
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(t, w.net, key.PublicKeyBytes())
+}
+
+// ReceiveAddress returns the receive address of the given type at the given
+// account and index.
+func (w *Wallet) ReceiveAddress(t Type, account uint32, index uint32) (string, error) {
+	return w.Address(t, account, false, index)
+}
+
+// ChangeAddress returns the change address of the given type at the given
+// account and index.
+func (w *Wallet) ChangeAddress(t Type, account uint32, index uint32) (string, error) {
+	return w.Address(t, account, true, index)
+}
+
+// Encode returns the address of the given type for a 33 byte compressed
+// public key on the given network.
+func Encode(t Type, net *Network, publicKey []byte) (string, error) {
+	if len(publicKey) != 33 {
+		return "", ErrInvalidPublicKey
+	}
+
+	switch t {
+	case P2PKH:
+		return base58.CheckEncode(append([]byte{net.PubKeyHashPrefix}, bip32.Hash160(publicKey)...)), nil
+
+	case P2SHP2WPKH:
+		// The redeem script is OP_0 <20 byte public key hash>.
+		redeemScript := append([]byte{0x00, 0x14}, bip32.Hash160(publicKey)...)
+		return base58.CheckEncode(append([]byte{net.ScriptHashPrefix}, bip32.Hash160(redeemScript)...)), nil
+
+	case P2WPKH:
+		return bech32.EncodeSegwitAddress(net.HRP, 0, bip32.Hash160(publicKey))
+
+	case P2TR:
+		outputKey, err := TaprootOutputKey(publicKey)
+		if err != nil {
+			return "", err
+		}
+		return bech32.EncodeSegwitAddress(net.HRP, 1, outputKey)
+
+	default:
+		return "", ErrInvalidType
+	}
+}
+
+// TaprootOutputKey returns the 32 byte x-only output key committing to the
+// internal public key with no script tree, as specified by BIP86.
+func TaprootOutputKey(publicKey []byte) ([]byte, error) {
+	if len(publicKey) != 33 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	// The internal key is the public key with an even Y coordinate.
+	internalKey, err := secp256k1.ParsePubKey(append([]byte{0x02}, publicKey[1:]...))
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	var tweak secp256k1.ModNScalar
+	if overflow := tweak.SetByteSlice(TaggedHash("TapTweak", publicKey[1:])); overflow {
+		return nil, ErrInvalidPublicKey
+	}
+
+	var point, tweakPoint secp256k1.JacobianPoint
+	internalKey.AsJacobian(&point)
+	secp256k1.ScalarBaseMultNonConst(&tweak, &tweakPoint)
+	secp256k1.AddNonConst(&point, &tweakPoint, &point)
+	point.ToAffine()
+
+	outputKey := point.X.Bytes()
+	return outputKey[:], nil
+}
+
+// TaggedHash returns the BIP340 tagged hash sha256(sha256(tag) ||
+// sha256(tag) || data).
+func TaggedHash(tag string, data []byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	hasher := sha256.New()
+	_, _ = hasher.Write(tagHash[:])
+	_, _ = hasher.Write(tagHash[:])
+	_, _ = hasher.Write(data)
+
+	return hasher.Sum(nil)
+}
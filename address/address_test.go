@@ -0,0 +1,112 @@
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+	"github.com/decen-one/go-bip39/bech32"
+)
+
+const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// Test vectors from BIP44 wallets, BIP49, BIP84 and BIP86.
+var testVectors = []struct {
+	addressType Type
+	net         *Network
+	change      bool
+	index       uint32
+	address     string
+}{
+	{P2PKH, Mainnet, false, 0, "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA"},
+	{P2SHP2WPKH, Mainnet, false, 0, "37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf"},
+	{P2SHP2WPKH, Testnet, false, 0, "2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2"},
+	{P2WPKH, Mainnet, false, 0, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"},
+	{P2WPKH, Mainnet, false, 1, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g"},
+	{P2WPKH, Mainnet, true, 0, "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el"},
+	{P2WPKH, Testnet, false, 0, "tb1q6rz28mcfaxtmd6v789l9rrlrusdprr9pqcpvkl"},
+	{P2TR, Mainnet, false, 0, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+	{P2TR, Mainnet, false, 1, "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh"},
+	{P2TR, Mainnet, true, 0, "bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7"},
+}
+
+func TestWalletAddress(t *testing.T) {
+	wallets := map[*Network]*Wallet{}
+	for _, net := range []*Network{Mainnet, Testnet} {
+		wallet, err := NewWallet("english", mnemonic, "", net)
+		assert.Nil(t, err)
+		wallets[net] = wallet
+	}
+
+	for _, vector := range testVectors {
+		address, err := wallets[vector.net].Address(vector.addressType, 0, vector.change, vector.index)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.address, address)
+	}
+
+	receive, err := wallets[Mainnet].ReceiveAddress(P2WPKH, 0, 1)
+	assert.Nil(t, err)
+	assert.EqualString(t, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", receive)
+
+	change, err := wallets[Mainnet].ChangeAddress(P2TR, 0, 0)
+	assert.Nil(t, err)
+	assert.EqualString(t, "bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7", change)
+
+	_, err = wallets[Mainnet].Address(Type(42), 0, false, 0)
+	assert.Equal(t, ErrInvalidType, err)
+}
+
+func TestRegtestAddress(t *testing.T) {
+	testnet, err := NewWallet("english", mnemonic, "", Testnet)
+	assert.Nil(t, err)
+	regtest, err := NewWallet("english", mnemonic, "", Regtest)
+	assert.Nil(t, err)
+
+	// Regtest shares the testnet derivation and only differs in its HRP.
+	for _, addressType := range []Type{P2WPKH, P2TR} {
+		testnetAddress, err := testnet.ReceiveAddress(addressType, 0, 0)
+		assert.Nil(t, err)
+		regtestAddress, err := regtest.ReceiveAddress(addressType, 0, 0)
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(regtestAddress, "bcrt1"))
+
+		version, program, err := bech32.DecodeSegwitAddress("tb", testnetAddress)
+		assert.Nil(t, err)
+		regtestVersion, regtestProgram, err := bech32.DecodeSegwitAddress("bcrt", regtestAddress)
+		assert.Nil(t, err)
+		assert.Equal(t, version, regtestVersion)
+		assert.EqualByteSlices(t, program, regtestProgram)
+	}
+
+	// Legacy addresses are identical on testnet and regtest.
+	testnetAddress, _ := testnet.ReceiveAddress(P2PKH, 0, 0)
+	regtestAddress, _ := regtest.ReceiveAddress(P2PKH, 0, 0)
+	assert.EqualString(t, testnetAddress, regtestAddress)
+}
+
+func TestTaprootOutputKey(t *testing.T) {
+	// BIP86 m/86'/0'/0'/0/0
+	internalKey, _ := hex.DecodeString("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+
+	for _, prefix := range []byte{0x02, 0x03} {
+		outputKey, err := TaprootOutputKey(append([]byte{prefix}, internalKey...))
+		assert.Nil(t, err)
+		assert.EqualString(t, "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c", hex.EncodeToString(outputKey))
+	}
+
+	_, err := TaprootOutputKey(internalKey)
+	assert.Equal(t, ErrInvalidPublicKey, err)
+}
+
+func TestPath(t *testing.T) {
+	assert.EqualString(t, "m/44'/0'/0'/0/5", Path(P2PKH, Mainnet, 0, false, 5))
+	assert.EqualString(t, "m/49'/1'/2'/1/0", Path(P2SHP2WPKH, Testnet, 2, true, 0))
+	assert.EqualString(t, "m/84'/1'/0'", AccountPath(P2WPKH, Regtest, 0))
+	assert.EqualString(t, "m/86'/0'/3'", AccountPath(P2TR, Mainnet, 3))
+}
+
+func TestNewWalletInvalidMnemonic(t *testing.T) {
+	_, err := NewWallet("english", "abandon abandon abandon", "", Mainnet)
+	assert.NotNil(t, err)
+}
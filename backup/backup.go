@@ -0,0 +1,332 @@
+// Package backup encrypts the entropy of mnemonics with a password for
+// storage.
+//
+// A backup is the header, the random salt and nonce and the XChaCha20-Poly1305
+// ciphertext of the entropy under a key derived from the password with scrypt
+// or Argon2id. The header describes the backup and is authenticated with the
+// entropy, so it can be inspected without the password but not altered:
+//
+//	magic "B39E" (4) | version (1) | language (1) | words (1) | KDF (1)
+//	| KDF parameters (scrypt: 3, Argon2id: 9) | salt (16) | nonce (24)
+//	| ciphertext (entropy + 16)
+//
+// The scrypt parameters are log2(N), r and p. The Argon2id parameters are the
+// time cost and the memory in KiB, both big endian uint32, and the number of
+// threads. Backups are also armored as PEM text.
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"strings"
+
+	"github.com/decen-one/go-bip39"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// Version is the version of the backup format written by Encrypt.
+	Version = 1
+
+	magic     = "B39E"
+	saltSize  = 16
+	keySize   = chacha20poly1305.KeySize
+	armorType = "BIP39 ENCRYPTED MNEMONIC"
+
+	// maxMemory, maxTime, maxScryptP and maxScryptWork bound the cost the
+	// KDF parameters of a backup may require, so that decrypting a crafted
+	// backup can not exhaust resources. maxMemory is the memory in KiB, 1 GiB
+	// as used by DefaultScrypt, maxTime the Argon2id time cost and
+	// maxScryptWork the product r * p * N, 4 times that of DefaultScrypt.
+	maxMemory     = 1 << 20
+	maxTime       = 16
+	maxScryptP    = 16
+	maxScryptWork = 1 << 25
+)
+
+// KDF is a password based key derivation function.
+type KDF byte
+
+const (
+	// Scrypt derives keys with scrypt.
+	Scrypt KDF = 1
+	// Argon2id derives keys with Argon2id.
+	Argon2id KDF = 2
+)
+
+// Params are the KDF and its cost parameters.
+type Params struct {
+	KDF KDF
+
+	// LogN, R and P are the scrypt parameters, with N = 2^LogN.
+	LogN uint8
+	R    uint8
+	P    uint8
+
+	// Time, Memory (in KiB) and Threads are the Argon2id parameters.
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+var (
+	// DefaultScrypt are the scrypt parameters recommended for file
+	// encryption, N = 2^20, r = 8 and p = 1.
+	DefaultScrypt = Params{KDF: Scrypt, LogN: 20, R: 8, P: 1}
+
+	// DefaultArgon2id are the Argon2id parameters recommended by RFC 9106 for
+	// memory constrained environments, t = 3 and 64 MiB.
+	DefaultArgon2id = Params{KDF: Argon2id, Time: 3, Memory: 64 << 10, Threads: 4}
+)
+
+// languages are the languages of the wordlists, indexed by their identifier
+// in the header.
+var languages = []string{
+	"english",
+	"chinese-simplified",
+	"chinese-traditional",
+	"czech",
+	"french",
+	"italian",
+	"japanese",
+	"korean",
+	"portuguese",
+	"spanish",
+}
+
+var (
+	// ErrInvalidFormat is returned when decoding data that is not a backup.
+	ErrInvalidFormat = errors.New("Invalid encrypted backup")
+
+	// ErrUnsupportedVersion is returned when decoding a backup of a newer
+	// format version.
+	ErrUnsupportedVersion = errors.New("Unsupported encrypted backup version")
+
+	// ErrInvalidParams is returned when the KDF parameters are unknown, too
+	// weak or too costly.
+	ErrInvalidParams = errors.New("Invalid KDF parameters")
+
+	// ErrDecryptionFailed is returned when the password is wrong or the backup
+	// has been altered.
+	ErrDecryptionFailed = errors.New("Wrong password or corrupted backup")
+)
+
+// Info describes a backup. It is readable without the password.
+type Info struct {
+	Version int
+	Lang    string
+	Words   int
+	Params  Params
+}
+
+// Encrypt returns the backup of a mnemonic encrypted with a password.
+func Encrypt(lang string, mnemonic string, password string, params Params) ([]byte, error) {
+	entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, saltSize)
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	_, _ = rand.Read(salt)  // err is always nil
+	_, _ = rand.Read(nonce) // err is always nil
+
+	info := &Info{Version: Version, Lang: strings.ToLower(lang), Words: len(strings.Fields(mnemonic)), Params: params}
+
+	return encrypt(info, entropy, password, salt, nonce)
+}
+
+func encrypt(info *Info, entropy []byte, password string, salt []byte, nonce []byte) ([]byte, error) {
+	header, err := info.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(info.Params, password, salt)
+	if err != nil {
+		return nil, err
+	}
+	aead, _ := chacha20poly1305.NewX(key) // err is always nil for a valid key size
+
+	data := append(append(header, salt...), nonce...)
+	return aead.Seal(data, nonce, entropy, header), nil
+}
+
+// Decrypt returns the language and the mnemonic of a backup.
+func Decrypt(data []byte, password string) (string, string, error) {
+	info, header, err := parse(data)
+	if err != nil {
+		return "", "", err
+	}
+
+	rest := data[len(header):]
+	if len(rest) < saltSize+chacha20poly1305.NonceSizeX+chacha20poly1305.Overhead {
+		return "", "", ErrInvalidFormat
+	}
+	salt := rest[:saltSize]
+	nonce := rest[saltSize : saltSize+chacha20poly1305.NonceSizeX]
+	ciphertext := rest[saltSize+chacha20poly1305.NonceSizeX:]
+
+	key, err := deriveKey(info.Params, password, salt)
+	if err != nil {
+		return "", "", err
+	}
+	aead, _ := chacha20poly1305.NewX(key) // err is always nil for a valid key size
+
+	entropy, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return "", "", ErrDecryptionFailed
+	}
+
+	mnemonic, err := bip39.NewMnemonic(info.Lang, entropy)
+	if err != nil || len(strings.Fields(mnemonic)) != info.Words {
+		return "", "", ErrInvalidFormat
+	}
+
+	return info.Lang, mnemonic, nil
+}
+
+// Inspect returns the description of a backup without decrypting it.
+func Inspect(data []byte) (*Info, error) {
+	info, _, err := parse(data)
+	return info, err
+}
+
+// Armor returns the PEM text of a backup.
+func Armor(data []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: armorType, Bytes: data}))
+}
+
+// Dearmor returns the backup of PEM text.
+func Dearmor(s string) ([]byte, error) {
+	block, rest := pem.Decode([]byte(strings.TrimSpace(s)))
+	if block == nil || block.Type != armorType || len(bytes.TrimSpace(rest)) != 0 {
+		return nil, ErrInvalidFormat
+	}
+
+	return block.Bytes, nil
+}
+
+// EncryptArmored returns the PEM armored backup of a mnemonic.
+func EncryptArmored(lang string, mnemonic string, password string, params Params) (string, error) {
+	data, err := Encrypt(lang, mnemonic, password, params)
+	if err != nil {
+		return "", err
+	}
+
+	return Armor(data), nil
+}
+
+// DecryptArmored returns the language and the mnemonic of a PEM armored
+// backup.
+func DecryptArmored(s string, password string) (string, string, error) {
+	data, err := Dearmor(s)
+	if err != nil {
+		return "", "", err
+	}
+
+	return Decrypt(data, password)
+}
+
+func (info *Info) marshal() ([]byte, error) {
+	language := -1
+	for i, lang := range languages {
+		if lang == info.Lang {
+			language = i
+		}
+	}
+	if language < 0 {
+		return nil, bip39.ErrInvalidLanguage
+	}
+	if err := info.Params.validate(); err != nil {
+		return nil, err
+	}
+
+	header := append([]byte(magic), byte(info.Version), byte(language), byte(info.Words), byte(info.Params.KDF))
+	switch info.Params.KDF {
+	case Scrypt:
+		header = append(header, info.Params.LogN, info.Params.R, info.Params.P)
+	case Argon2id:
+		header = binary.BigEndian.AppendUint32(header, info.Params.Time)
+		header = binary.BigEndian.AppendUint32(header, info.Params.Memory)
+		header = append(header, info.Params.Threads)
+	}
+
+	return header, nil
+}
+
+// parse returns the description and the header of a backup.
+func parse(data []byte) (*Info, []byte, error) {
+	const fixedSize = len(magic) + 4
+	if len(data) < fixedSize || string(data[:len(magic)]) != magic {
+		return nil, nil, ErrInvalidFormat
+	}
+
+	version, language, words, kdf := data[4], data[5], data[6], KDF(data[7])
+	if version != Version {
+		return nil, nil, ErrUnsupportedVersion
+	}
+	if int(language) >= len(languages) || words%3 != 0 || words < 12 || words > 24 {
+		return nil, nil, ErrInvalidFormat
+	}
+
+	info := &Info{Version: int(version), Lang: languages[language], Words: int(words), Params: Params{KDF: kdf}}
+	params := data[fixedSize:]
+	switch kdf {
+	case Scrypt:
+		if len(params) < 3 {
+			return nil, nil, ErrInvalidFormat
+		}
+		info.Params.LogN, info.Params.R, info.Params.P = params[0], params[1], params[2]
+		params = params[:3]
+	case Argon2id:
+		if len(params) < 9 {
+			return nil, nil, ErrInvalidFormat
+		}
+		info.Params.Time = binary.BigEndian.Uint32(params)
+		info.Params.Memory = binary.BigEndian.Uint32(params[4:])
+		info.Params.Threads = params[8]
+		params = params[:9]
+	default:
+		return nil, nil, ErrInvalidParams
+	}
+	if err := info.Params.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return info, data[:fixedSize+len(params)], nil
+}
+
+func (p Params) validate() error {
+	switch p.KDF {
+	case Scrypt:
+		// scrypt uses 128 * r * N bytes, and its time is proportional to
+		// r * p * N.
+		if p.LogN < 10 || p.LogN > 30 || p.R == 0 || p.P == 0 || p.P > maxScryptP ||
+			uint64(p.R)<<p.LogN/8 > maxMemory || uint64(p.R)*uint64(p.P)<<p.LogN > maxScryptWork {
+			return ErrInvalidParams
+		}
+	case Argon2id:
+		if p.Time == 0 || p.Time > maxTime || p.Threads == 0 || p.Memory < 8*uint32(p.Threads) || p.Memory > maxMemory {
+			return ErrInvalidParams
+		}
+	default:
+		return ErrInvalidParams
+	}
+
+	return nil
+}
+
+// deriveKey is a variable so that tests can check when the KDF runs.
+var deriveKey = func(p Params, password string, salt []byte) ([]byte, error) {
+	if p.KDF == Argon2id {
+		return argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, keySize), nil
+	}
+
+	return scrypt.Key([]byte(password), salt, 1<<p.LogN, int(p.R), int(p.P), keySize)
+}
@@ -0,0 +1,278 @@
+package backup
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// Cheap parameters to keep the tests fast.
+var (
+	testScrypt   = Params{KDF: Scrypt, LogN: 10, R: 8, P: 1}
+	testArgon2id = Params{KDF: Argon2id, Time: 1, Memory: 64, Threads: 1}
+)
+
+// Backups of the entropy 7f7f...7f with the password "TREZOR", a salt of 01
+// bytes and a nonce of 02 bytes. They must stay readable by later versions.
+var goldenBackups = []struct {
+	armored string
+	params  Params
+}{
+	{
+		armored: `-----BEGIN BIP39 ENCRYPTED MNEMONIC-----
+QjM5RQEADAEKCAEBAQEBAQEBAQEBAQEBAQEBAgICAgICAgICAgICAgICAgICAgIC
+AgIChYIKGuVsP0fMXNLy5a82zmpi/TeOjhv8zz5ISYpHVp4=
+-----END BIP39 ENCRYPTED MNEMONIC-----
+`,
+		params: testScrypt,
+	},
+	{
+		armored: `-----BEGIN BIP39 ENCRYPTED MNEMONIC-----
+QjM5RQEADAIAAAABAAAAQAEBAQEBAQEBAQEBAQEBAQEBAgICAgICAgICAgICAgIC
+AgICAgICAgIC1WrPdMISjTJ1WNiKP0JzJ4hsAkox3elql/diC7vm4Rc=
+-----END BIP39 ENCRYPTED MNEMONIC-----
+`,
+		params: testArgon2id,
+	},
+}
+
+const goldenMnemonic = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+
+func TestGolden(t *testing.T) {
+	for _, golden := range goldenBackups {
+		lang, mnemonic, err := DecryptArmored(golden.armored, "TREZOR")
+		assert.Nil(t, err)
+		assert.EqualString(t, "english", lang)
+		assert.EqualString(t, goldenMnemonic, mnemonic)
+
+		data, err := Dearmor(golden.armored)
+		assert.Nil(t, err)
+
+		info, err := Inspect(data)
+		assert.Nil(t, err)
+		assert.Equal(t, Version, info.Version)
+		assert.EqualString(t, "english", info.Lang)
+		assert.Equal(t, 12, info.Words)
+		assert.Equal(t, golden.params, info.Params)
+
+		encrypted, err := encrypt(info, bytes.Repeat([]byte{0x7f}, 16), "TREZOR", bytes.Repeat([]byte{1}, 16), bytes.Repeat([]byte{2}, 24))
+		assert.Nil(t, err)
+		assert.EqualByteSlices(t, data, encrypted)
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	for _, lang := range languages {
+		for _, size := range []int{12, 18, 24} {
+			mnemonic, err := bip39.NewRandMnemonic(lang, size)
+			assert.Nil(t, err)
+
+			for _, params := range []Params{testScrypt, testArgon2id} {
+				data, err := Encrypt(lang, mnemonic, "correct horse battery staple", params)
+				assert.Nil(t, err)
+
+				info, err := Inspect(data)
+				assert.Nil(t, err)
+				assert.EqualString(t, lang, info.Lang)
+				assert.Equal(t, size, info.Words)
+
+				decryptedLang, decrypted, err := Decrypt(data, "correct horse battery staple")
+				assert.Nil(t, err)
+				assert.EqualString(t, lang, decryptedLang)
+				assert.EqualString(t, mnemonic, decrypted)
+			}
+		}
+	}
+
+	// Encrypting twice uses a different salt and nonce.
+	first, err := Encrypt("english", goldenMnemonic, "TREZOR", testScrypt)
+	assert.Nil(t, err)
+	second, err := Encrypt("english", goldenMnemonic, "TREZOR", testScrypt)
+	assert.Nil(t, err)
+	assert.False(t, bytes.Equal(first, second))
+
+	_, err = Encrypt("english", "legal winner thank year wave sausage worth useful legal winner thank thank", "TREZOR", testScrypt)
+	assert.Equal(t, bip39.ErrChecksumIncorrect, err)
+
+	for _, params := range []Params{
+		{},
+		{KDF: Scrypt, LogN: 9, R: 8, P: 1},
+		{KDF: Scrypt, LogN: 30, R: 8, P: 1},
+		{KDF: Scrypt, LogN: 10, R: 0, P: 1},
+		{KDF: Scrypt, LogN: 10, R: 8, P: 17},
+		{KDF: Scrypt, LogN: 21, R: 8, P: 1},
+		{KDF: Scrypt, LogN: 20, R: 8, P: 8},
+		{KDF: Argon2id, Time: 0, Memory: 64, Threads: 1},
+		{KDF: Argon2id, Time: 1, Memory: 7, Threads: 1},
+		{KDF: Argon2id, Time: 1, Memory: 2 << 20, Threads: 1},
+		{KDF: Argon2id, Time: 17, Memory: 64, Threads: 1},
+		{KDF: 3, Time: 1, Memory: 64, Threads: 1},
+	} {
+		_, err := Encrypt("english", goldenMnemonic, "TREZOR", params)
+		assert.Equal(t, ErrInvalidParams, err)
+	}
+}
+
+func TestTamper(t *testing.T) {
+	for _, params := range []Params{testScrypt, testArgon2id} {
+		data, err := Encrypt("english", goldenMnemonic, "TREZOR", params)
+		assert.Nil(t, err)
+
+		_, _, err = Decrypt(data, "trezor")
+		assert.Equal(t, ErrDecryptionFailed, err)
+
+		// Every bit of the header, salt, nonce and ciphertext is protected.
+		// The KDF parameters are checked apart as flipping their high bits
+		// makes the KDF too costly for a test.
+		_, header, _ := parse(data)
+		for i := range data {
+			if i >= 8 && i < len(header) {
+				continue
+			}
+			for bit := 0; bit < 8; bit++ {
+				tampered := append([]byte{}, data...)
+				tampered[i] ^= 1 << bit
+
+				_, _, err := Decrypt(tampered, "TREZOR")
+				assert.NotNil(t, err)
+			}
+		}
+
+		for i := 0; i < len(data); i++ {
+			_, _, err := Decrypt(data[:i], "TREZOR")
+			assert.NotNil(t, err)
+		}
+
+		_, _, err = Decrypt(append(data, 0), "TREZOR")
+		assert.Equal(t, ErrDecryptionFailed, err)
+	}
+
+	for _, params := range []Params{
+		{KDF: Scrypt, LogN: 11, R: 8, P: 1},
+		{KDF: Scrypt, LogN: 10, R: 9, P: 1},
+		{KDF: Scrypt, LogN: 10, R: 8, P: 2},
+		{KDF: Argon2id, Time: 2, Memory: 64, Threads: 1},
+		{KDF: Argon2id, Time: 1, Memory: 65, Threads: 1},
+		{KDF: Argon2id, Time: 1, Memory: 64, Threads: 2},
+	} {
+		original := testScrypt
+		if params.KDF == Argon2id {
+			original = testArgon2id
+		}
+		data, err := Encrypt("english", goldenMnemonic, "TREZOR", original)
+		assert.Nil(t, err)
+
+		header, err := (&Info{Version: Version, Lang: "english", Words: 12, Params: params}).marshal()
+		assert.Nil(t, err)
+		copy(data, header)
+
+		_, _, err = Decrypt(data, "TREZOR")
+		assert.Equal(t, ErrDecryptionFailed, err)
+	}
+}
+
+func TestOversizedParams(t *testing.T) {
+	defer func(original func(Params, string, []byte) ([]byte, error)) { deriveKey = original }(deriveKey)
+	deriveKey = func(p Params, password string, salt []byte) ([]byte, error) {
+		t.Errorf("KDF run with %+v", p)
+		return nil, ErrInvalidParams
+	}
+
+	// The limits allow the default parameters.
+	assert.Nil(t, DefaultScrypt.validate())
+	assert.Nil(t, DefaultArgon2id.validate())
+
+	data, err := Dearmor(goldenBackups[0].armored)
+	assert.Nil(t, err)
+
+	// The scrypt parameters log2(N), r and p follow the fixed header.
+	for _, params := range [][3]byte{
+		{30, 255, 255},
+		{20, 8, 17},
+		{21, 8, 1},
+		{20, 8, 8},
+		{20, 255, 1},
+	} {
+		tampered := append([]byte{}, data...)
+		copy(tampered[8:], params[:])
+
+		_, _, err := Decrypt(tampered, "TREZOR")
+		assert.Equal(t, ErrInvalidParams, err)
+	}
+
+	// The Argon2id time cost and memory are big endian uint32.
+	data, err = Dearmor(goldenBackups[1].armored)
+	assert.Nil(t, err)
+	for _, params := range [][9]byte{
+		{0, 0, 0, 17, 0, 0, 0, 64, 1},
+		{0, 0, 0, 1, 0, 0x10, 0, 1, 1},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	} {
+		tampered := append([]byte{}, data...)
+		copy(tampered[8:], params[:])
+
+		_, _, err := Decrypt(tampered, "TREZOR")
+		assert.Equal(t, ErrInvalidParams, err)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	data, err := Dearmor(goldenBackups[0].armored)
+	assert.Nil(t, err)
+
+	for _, invalid := range []struct {
+		offset int
+		value  byte
+		err    error
+	}{
+		{0, 'X', ErrInvalidFormat},
+		{4, 2, ErrUnsupportedVersion},
+		{5, byte(len(languages)), ErrInvalidFormat},
+		{6, 13, ErrInvalidFormat},
+		{6, 27, ErrInvalidFormat},
+		{7, 0, ErrInvalidParams},
+		{8, 40, ErrInvalidParams},
+	} {
+		tampered := append([]byte{}, data...)
+		tampered[invalid.offset] = invalid.value
+
+		_, err := Inspect(tampered)
+		assert.Equal(t, invalid.err, err)
+	}
+
+	// A word count that does not match the entropy is detected by the
+	// authentication of the header.
+	tampered := append([]byte{}, data...)
+	tampered[6] = 24
+	_, err = Inspect(tampered)
+	assert.Nil(t, err)
+	_, _, err = Decrypt(tampered, "TREZOR")
+	assert.Equal(t, ErrDecryptionFailed, err)
+}
+
+func TestArmor(t *testing.T) {
+	japanese, err := bip39.NewMnemonic("japanese", bytes.Repeat([]byte{0x7f}, 16))
+	assert.Nil(t, err)
+
+	armored, err := EncryptArmored("japanese", japanese, "TREZOR", testArgon2id)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(armored, "-----BEGIN BIP39 ENCRYPTED MNEMONIC-----\n"))
+
+	lang, mnemonic, err := DecryptArmored("\n"+armored+"\n", "TREZOR")
+	assert.Nil(t, err)
+	assert.EqualString(t, "japanese", lang)
+	assert.EqualString(t, japanese, mnemonic)
+
+	for _, invalid := range []string{
+		"",
+		strings.Replace(armored, "BIP39 ENCRYPTED MNEMONIC", "PRIVATE KEY", 2),
+		armored + armored,
+		strings.Replace(armored, "\n", "!\n", 2),
+	} {
+		_, err := Dearmor(invalid)
+		assert.Equal(t, ErrInvalidFormat, err)
+	}
+}
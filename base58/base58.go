@@ -0,0 +1,119 @@
+// Package base58 implements the Bitcoin base58 and base58check encodings.
+package base58
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	// ErrInvalidCharacter is returned when decoding a string containing a
+	// character outside of the base58 alphabet.
+	ErrInvalidCharacter = errors.New("Invalid base58 character")
+
+	// ErrChecksumIncorrect is returned when a base58check string has the
+	// incorrect checksum.
+	ErrChecksumIncorrect = errors.New("Checksum incorrect")
+
+	// ErrInvalidFormat is returned when a base58check string is too short to
+	// contain a checksum.
+	ErrInvalidFormat = errors.New("Invalid base58check format")
+
+	bigRadix = big.NewInt(58)
+
+	// decodeMap is a reverse lookup map for alphabet.
+	decodeMap [256]int
+)
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		decodeMap[alphabet[i]] = i
+	}
+}
+
+// Encode encodes data to base58. Each leading zero byte is encoded as a
+// leading '1'.
+func Encode(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+
+	encoded := make([]byte, 0, len(data)*138/100+1)
+	for x.Sign() > 0 {
+		x.DivMod(x, bigRadix, mod)
+		encoded = append(encoded, alphabet[mod.Int64()])
+	}
+
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, alphabet[0])
+	}
+
+	// Reverse the little endian digits.
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	return string(encoded)
+}
+
+// Decode decodes a base58 string.
+func Decode(s string) ([]byte, error) {
+	x := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		digit := decodeMap[s[i]]
+		if digit < 0 {
+			return nil, ErrInvalidCharacter
+		}
+		x.Mul(x, bigRadix)
+		x.Add(x, big.NewInt(int64(digit)))
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), x.Bytes()...), nil
+}
+
+// CheckEncode appends the first four bytes of the double SHA256 of data to
+// data and encodes the result to base58.
+func CheckEncode(data []byte) string {
+	return Encode(append(append([]byte{}, data...), checksum(data)...))
+}
+
+// CheckDecode decodes a base58check string and verifies its checksum. The
+// payload is returned without the checksum.
+func CheckDecode(s string) ([]byte, error) {
+	decoded, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < 4 {
+		return nil, ErrInvalidFormat
+	}
+
+	payload := decoded[:len(decoded)-4]
+	if !bytes.Equal(checksum(payload), decoded[len(decoded)-4:]) {
+		return nil, ErrChecksumIncorrect
+	}
+
+	return payload, nil
+}
+
+// checksum returns the first four bytes of sha256(sha256(data)).
+func checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+
+	return second[:4]
+}
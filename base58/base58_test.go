@@ -0,0 +1,57 @@
+package base58
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+)
+
+var testVectors = []struct {
+	data    string
+	encoded string
+}{
+	{"", ""},
+	{"61", "2g"},
+	{"626262", "a3gV"},
+	{"636363", "aPEr"},
+	{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+	{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	{"516b6fcd0f", "ABnLTmg"},
+	{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+	{"572e4794", "3EFU7m"},
+	{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+	{"10c8511e", "Rt5zm"},
+	{"00000000000000000000", "1111111111"},
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, vector := range testVectors {
+		data, err := hex.DecodeString(vector.data)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.encoded, Encode(data))
+
+		decoded, err := Decode(vector.encoded)
+		assert.Nil(t, err)
+		assert.EqualByteSlices(t, data, decoded)
+	}
+
+	_, err := Decode("0OIl")
+	assert.Equal(t, ErrInvalidCharacter, err)
+}
+
+func TestCheckEncodeDecode(t *testing.T) {
+	// The address of the uncompressed public key of private key 1.
+	payload, _ := hex.DecodeString("0091b24bf9f5288532960ac687abb035127b1d28a5")
+	assert.EqualString(t, "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm", CheckEncode(payload))
+
+	decoded, err := CheckDecode("1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm")
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, payload, decoded)
+
+	_, err = CheckDecode("1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZn")
+	assert.Equal(t, ErrChecksumIncorrect, err)
+
+	_, err = CheckDecode("1")
+	assert.Equal(t, ErrInvalidFormat, err)
+}
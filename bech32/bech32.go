@@ -0,0 +1,244 @@
+// Package bech32 implements the bech32 (BIP173) and bech32m (BIP350)
+// encodings and segregated witness addresses built on them.
+package bech32
+
+import (
+	"errors"
+	"strings"
+)
+
+// Variant selects the checksum constant of the encoding.
+type Variant int
+
+const (
+	// Bech32 is the original encoding of BIP173.
+	Bech32 Variant = iota
+
+	// Bech32m is the encoding of BIP350, used for witness version 1 and above.
+	Bech32m
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var checksumConstants = map[Variant]uint32{
+	Bech32:  1,
+	Bech32m: 0x2bc830a3,
+}
+
+var (
+	// ErrInvalidString is returned when decoding a malformed string.
+	ErrInvalidString = errors.New("Invalid bech32 string")
+
+	// ErrChecksumIncorrect is returned when a string has the incorrect checksum.
+	ErrChecksumIncorrect = errors.New("Checksum incorrect")
+
+	// ErrInvalidPadding is returned when converting data with non-zero or
+	// excess padding bits.
+	ErrInvalidPadding = errors.New("Invalid padding")
+
+	// ErrInvalidWitnessProgram is returned when a segwit address has an
+	// invalid witness version or program.
+	ErrInvalidWitnessProgram = errors.New("Invalid witness program")
+)
+
+// decodeMap is a reverse lookup map for charset.
+var decodeMap [128]int8
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = -1
+	}
+	for i := 0; i < len(charset); i++ {
+		decodeMap[charset[i]] = int8(i)
+	}
+}
+
+// Encode encodes the human readable part and 5 bit data with the given
+// variant.
+func Encode(hrp string, data []byte, variant Variant) string {
+	hrp = strings.ToLower(hrp)
+	checksum := createChecksum(hrp, data, variant)
+
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(data) + 6)
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range append(append([]byte{}, data...), checksum...) {
+		sb.WriteByte(charset[d])
+	}
+
+	return sb.String()
+}
+
+// Decode decodes a bech32 or bech32m string into its human readable part and
+// 5 bit data, and reports the variant of its checksum.
+func Decode(s string) (string, []byte, Variant, error) {
+	if len(s) > 90 {
+		return "", nil, 0, ErrInvalidString
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, ErrInvalidString
+	}
+	s = strings.ToLower(s)
+
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, 0, ErrInvalidString
+	}
+
+	hrp := s[:pos]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, ErrInvalidString
+		}
+	}
+
+	data := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		if s[i] >= 128 || decodeMap[s[i]] < 0 {
+			return "", nil, 0, ErrInvalidString
+		}
+		data = append(data, byte(decodeMap[s[i]]))
+	}
+
+	checksum := polymod(append(expandHRP(hrp), data...))
+	for variant, constant := range checksumConstants {
+		if checksum == constant {
+			return hrp, data[:len(data)-6], variant, nil
+		}
+	}
+
+	return "", nil, 0, ErrChecksumIncorrect
+}
+
+// ConvertBits regroups data from fromBits to toBits per byte. When pad is
+// false, incomplete groups of zero bits are dropped and any other remainder
+// is an error.
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var (
+		acc    uint32
+		bits   uint
+		maxV   = uint32(1)<<toBits - 1
+		result = make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	)
+
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, ErrInvalidPadding
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxV))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxV))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxV != 0 {
+		return nil, ErrInvalidPadding
+	}
+
+	return result, nil
+}
+
+// EncodeSegwitAddress encodes a witness program as a segwit address. Version
+// 0 uses bech32 and later versions bech32m.
+func EncodeSegwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if err := validateWitnessProgram(version, program); err != nil {
+		return "", err
+	}
+
+	data, _ := ConvertBits(program, 8, 5, true) // err is always nil when padding
+
+	variant := Bech32m
+	if version == 0 {
+		variant = Bech32
+	}
+
+	return Encode(hrp, append([]byte{version}, data...), variant), nil
+}
+
+// DecodeSegwitAddress decodes a segwit address with the expected human
+// readable part into its witness version and program.
+func DecodeSegwitAddress(hrp string, address string) (byte, []byte, error) {
+	decodedHRP, data, variant, err := Decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if decodedHRP != strings.ToLower(hrp) || len(data) < 1 {
+		return 0, nil, ErrInvalidWitnessProgram
+	}
+
+	version := data[0]
+	if (version == 0) != (variant == Bech32) {
+		return 0, nil, ErrInvalidWitnessProgram
+	}
+
+	program, err := ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := validateWitnessProgram(version, program); err != nil {
+		return 0, nil, err
+	}
+
+	return version, program, nil
+}
+
+func validateWitnessProgram(version byte, program []byte) error {
+	if version > 16 || len(program) < 2 || len(program) > 40 {
+		return ErrInvalidWitnessProgram
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return ErrInvalidWitnessProgram
+	}
+
+	return nil
+}
+
+func polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func expandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+
+	return expanded
+}
+
+func createChecksum(hrp string, data []byte, variant Variant) []byte {
+	values := append(expandHRP(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := polymod(values) ^ checksumConstants[variant]
+
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte(mod>>(5*(5-i))) & 31
+	}
+
+	return checksum
+}
@@ -0,0 +1,298 @@
+// Package bip32 implements BIP32 hierarchical deterministic keys on the
+// secp256k1 curve, created from the seed returned by bip39.NewSeed.
+package bip32
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/decen-one/go-bip39/base58"
+	"github.com/decen-one/go-bip39/slip10"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// FirstHardenedIndex is the index of the first hardened child key.
+const FirstHardenedIndex = slip10.FirstHardenedIndex
+
+// Version bytes of serialized extended keys.
+const (
+	MainnetPrivateVersion = uint32(0x0488ade4) // xprv
+	MainnetPublicVersion  = uint32(0x0488b21e) // xpub
+	TestnetPrivateVersion = uint32(0x04358394) // tprv
+	TestnetPublicVersion  = uint32(0x043587cf) // tpub
+)
+
+// publicVersions maps private key versions to their public key versions.
+var publicVersions = map[uint32]uint32{
+	MainnetPrivateVersion: MainnetPublicVersion,
+	TestnetPrivateVersion: TestnetPublicVersion,
+}
+
+var (
+	// ErrInvalidSeed is returned when the seed is not between 128 and 512 bits.
+	ErrInvalidSeed = errors.New("Seed length must be [16, 64] bytes")
+
+	// ErrInvalidKey is returned when a derived key is not valid. This happens
+	// with a probability lower than 1 in 2^127.
+	ErrInvalidKey = errors.New("Invalid key")
+
+	// ErrHardenedPublicDerivation is returned when trying to derive a hardened
+	// child from a public key.
+	ErrHardenedPublicDerivation = errors.New("Can not derive hardened child from public key")
+
+	// ErrInvalidSerialization is returned when a serialized extended key is
+	// malformed.
+	ErrInvalidSerialization = errors.New("Invalid serialized extended key")
+)
+
+// Key is a BIP32 extended private or public key.
+type Key struct {
+	Version           uint32
+	Depth             byte
+	ParentFingerprint uint32
+	ChildNumber       uint32
+	ChainCode         []byte
+
+	// Key is the 32 byte private key or the 33 byte compressed public key.
+	Key       []byte
+	IsPrivate bool
+}
+
+// NewMasterKey creates the mainnet master key from a seed such as the output
+// of bip39.NewSeed.
+func NewMasterKey(seed []byte) (*Key, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrInvalidSeed
+	}
+
+	I := hmacSHA512([]byte("Bitcoin seed"), seed)
+	if !validPrivateKey(I[:32]) {
+		return nil, ErrInvalidKey
+	}
+
+	return &Key{
+		Version:   MainnetPrivateVersion,
+		ChainCode: I[32:],
+		Key:       I[:32],
+		IsPrivate: true,
+	}, nil
+}
+
+// NewChildKey derives the child key at the given index. Indexes at or above
+// FirstHardenedIndex produce hardened children, which can only be derived
+// from private keys.
+func (k *Key) NewChildKey(index uint32) (*Key, error) {
+	hardened := index >= FirstHardenedIndex
+	if hardened && !k.IsPrivate {
+		return nil, ErrHardenedPublicDerivation
+	}
+
+	data := make([]byte, 37)
+	if hardened {
+		copy(data[1:33], k.Key)
+	} else {
+		copy(data[:33], k.PublicKeyBytes())
+	}
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	I := hmacSHA512(k.ChainCode, data)
+
+	var il secp256k1.ModNScalar
+	if overflow := il.SetByteSlice(I[:32]); overflow {
+		return nil, ErrInvalidKey
+	}
+
+	child := &Key{
+		Version:           k.Version,
+		Depth:             k.Depth + 1,
+		ParentFingerprint: k.Fingerprint(),
+		ChildNumber:       index,
+		ChainCode:         I[32:],
+		IsPrivate:         k.IsPrivate,
+	}
+
+	if k.IsPrivate {
+		var parent secp256k1.ModNScalar
+		parent.SetByteSlice(k.Key)
+		il.Add(&parent)
+		if il.IsZero() {
+			return nil, ErrInvalidKey
+		}
+
+		key := il.Bytes()
+		child.Key = key[:]
+		return child, nil
+	}
+
+	parent, err := secp256k1.ParsePubKey(k.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	var point, tweak secp256k1.JacobianPoint
+	parent.AsJacobian(&point)
+	secp256k1.ScalarBaseMultNonConst(&il, &tweak)
+	secp256k1.AddNonConst(&point, &tweak, &point)
+	if (point.X.IsZero() && point.Y.IsZero()) || point.Z.IsZero() {
+		return nil, ErrInvalidKey
+	}
+	point.ToAffine()
+
+	child.Key = secp256k1.NewPublicKey(&point.X, &point.Y).SerializeCompressed()
+	return child, nil
+}
+
+// Derive follows the given path, such as "m/44'/0'/0'/0/0", from k.
+func (k *Key) Derive(path string) (*Key, error) {
+	indexes, err := slip10.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := k
+	for _, index := range indexes {
+		key, err = key.NewChildKey(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// PublicKey returns the extended public key of k.
+func (k *Key) PublicKey() *Key {
+	if !k.IsPrivate {
+		return k
+	}
+
+	version, ok := publicVersions[k.Version]
+	if !ok {
+		version = k.Version
+	}
+
+	return &Key{
+		Version:           version,
+		Depth:             k.Depth,
+		ParentFingerprint: k.ParentFingerprint,
+		ChildNumber:       k.ChildNumber,
+		ChainCode:         k.ChainCode,
+		Key:               k.PublicKeyBytes(),
+	}
+}
+
+// PublicKeyBytes returns the 33 byte compressed public key of k.
+func (k *Key) PublicKeyBytes() []byte {
+	if !k.IsPrivate {
+		return k.Key
+	}
+
+	return secp256k1.PrivKeyFromBytes(k.Key).PubKey().SerializeCompressed()
+}
+
+// Fingerprint returns the first 32 bits of the HASH160 of the public key.
+func (k *Key) Fingerprint() uint32 {
+	return binary.BigEndian.Uint32(Hash160(k.PublicKeyBytes()))
+}
+
+// Serialize returns the 78 byte serialization of k.
+func (k *Key) Serialize() []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, k.Version) // Writes to a bytes.Buffer never fail
+	buf.WriteByte(k.Depth)
+	_ = binary.Write(&buf, binary.BigEndian, k.ParentFingerprint)
+	_ = binary.Write(&buf, binary.BigEndian, k.ChildNumber)
+	buf.Write(k.ChainCode)
+	if k.IsPrivate {
+		buf.WriteByte(0)
+	}
+	buf.Write(k.Key)
+
+	return buf.Bytes()
+}
+
+// String returns the base58check serialization of k, such as "xprv..." or
+// "xpub...".
+func (k *Key) String() string {
+	return base58.CheckEncode(k.Serialize())
+}
+
+// Deserialize parses a 78 byte serialized extended key.
+func Deserialize(data []byte) (*Key, error) {
+	if len(data) != 78 {
+		return nil, ErrInvalidSerialization
+	}
+
+	k := &Key{
+		Version:           binary.BigEndian.Uint32(data[:4]),
+		Depth:             data[4],
+		ParentFingerprint: binary.BigEndian.Uint32(data[5:9]),
+		ChildNumber:       binary.BigEndian.Uint32(data[9:13]),
+		ChainCode:         append([]byte{}, data[13:45]...),
+		IsPrivate:         data[45] == 0,
+	}
+
+	if k.Depth == 0 && (k.ParentFingerprint != 0 || k.ChildNumber != 0) {
+		return nil, ErrInvalidSerialization
+	}
+
+	// Private keys must use a private version and public keys a public one.
+	_, privateVersion := publicVersions[k.Version]
+	if privateVersion != k.IsPrivate {
+		return nil, ErrInvalidSerialization
+	}
+
+	if k.IsPrivate {
+		k.Key = append([]byte{}, data[46:]...)
+		if !validPrivateKey(k.Key) {
+			return nil, ErrInvalidKey
+		}
+	} else {
+		k.Key = append([]byte{}, data[45:]...)
+		if _, err := secp256k1.ParsePubKey(k.Key); err != nil {
+			return nil, ErrInvalidKey
+		}
+	}
+
+	return k, nil
+}
+
+// B58Deserialize parses a base58check serialized extended key such as
+// "xprv..." or "xpub...".
+func B58Deserialize(s string) (*Key, error) {
+	data, err := base58.CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return Deserialize(data)
+}
+
+// Hash160 returns ripemd160(sha256(data)).
+func Hash160(data []byte) []byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	_, _ = hasher.Write(sha[:])
+
+	return hasher.Sum(nil)
+}
+
+// validPrivateKey reports whether key is in the range [1, n-1].
+func validPrivateKey(key []byte) bool {
+	var k secp256k1.ModNScalar
+	overflow := k.SetByteSlice(key)
+
+	return !overflow && !k.IsZero()
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	_, _ = mac.Write(data)
+
+	return mac.Sum(nil)
+}
@@ -1,45 +1,38 @@
 package bip39
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
-	"crypto/sha512"
-	"encoding/binary"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"math/big"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/decen-one/go-bip39/bip32"
 	"github.com/decen-one/go-bip39/wordlist"
-	"golang.org/x/crypto/pbkdf2"
-	"golang.org/x/text/unicode/norm"
 )
 
-var (
-	// Some bitwise operands for working with big.Ints.
-	last11BitsMask  = big.NewInt(2047)
-	shift11BitsMask = big.NewInt(2048)
-	bigOne          = big.NewInt(1)
-	bigTwo          = big.NewInt(2)
-
-	// wordLengthChecksumMasksMapping is used to isolate the checksum bits from
-	//the entropy+checksum byte array.
-	wordLengthChecksumMasksMapping = map[int]*big.Int{
-		12: big.NewInt(15),
-		15: big.NewInt(31),
-		18: big.NewInt(63),
-		21: big.NewInt(127),
-		24: big.NewInt(255),
-	}
-
-	// wordLengthChecksumShiftMapping is used to lookup the number of operand
-	// for shifting bits to handle checksums.
-	wordLengthChecksumShiftMapping = map[int]*big.Int{
-		12: big.NewInt(16),
-		15: big.NewInt(8),
-		18: big.NewInt(4),
-		21: big.NewInt(2),
-	}
+const (
+	// seedIterations is the number of PBKDF2 iterations of the seed.
+	seedIterations = 2048
+
+	// contextCheckInterval is the number of PBKDF2 iterations between checks
+	// of the context in NewSeedContext.
+	contextCheckInterval = 128
+
+	// maxWords is the number of words of the longest mnemonics.
+	maxWords = 24
+
+	// packedLength is the size of the buffers holding the longest entropy
+	// followed by its checksum, with 2 bytes to spare so that each 11 bit
+	// word index can be read and written as 3 bytes.
+	packedLength = 32 + 1 + 2
 )
 
 // wordList is the set of words to use per each language.
@@ -69,6 +62,10 @@ var (
 
 	// ErrChecksumIncorrect is returned when entropy has the incorrect checksum.
 	ErrChecksumIncorrect = errors.New("Checksum incorrect")
+
+	// ErrInvalidFingerprint is returned when trying to use a malformed master
+	// key fingerprint.
+	ErrInvalidFingerprint = errors.New("Fingerprint must be 4 hex encoded bytes")
 )
 
 func init() {
@@ -93,6 +90,7 @@ func SetWordMap() {
 		for i, v := range list {
 			wordMap[k][v] = i
 		}
+		wordTables[k] = newWordTable(list)
 	}
 
 }
@@ -175,46 +173,48 @@ func EntropyFromMnemonic(lang string, mnemonic string) ([]byte, error) {
 		return nil, ErrInvalidMnemonic
 	}
 
-	// Decode the words into a big.Int.
-	var (
-		wordBytes [2]byte
-		b         = big.NewInt(0)
-	)
+	b := []byte(mnemonic)
+	defer wipe(b)
 
-	for _, v := range mnemonicSlice {
-		index, found := wordMap[lang][v]
-		if !found {
-			return nil, fmt.Errorf("word `%v` not found in reverse map", v)
+	words := bytes.Fields(b)
+	entropy := make([]byte, len(mnemonicSlice)/3*4)
+	unknown, valid := decodeWords(lang, words, entropy)
+	if unknown >= 0 || !valid {
+		wipe(entropy)
+	}
+	if unknown >= 0 {
+		if atomic.LoadInt32(&constantTime) == 1 {
+			return nil, ErrInvalidMnemonic
 		}
-
-		binary.BigEndian.PutUint16(wordBytes[:], uint16(index))
-		b.Mul(b, shift11BitsMask)
-		b.Or(b, big.NewInt(0).SetBytes(wordBytes[:]))
+		return nil, fmt.Errorf("word `%v` not found in reverse map", mnemonicSlice[unknown])
+	}
+	if !valid {
+		return nil, ErrChecksumIncorrect
 	}
 
-	// Build and add the checksum to the big.Int.
-	checksum := big.NewInt(0)
-	checksumMask := wordLengthChecksumMasksMapping[len(mnemonicSlice)]
-	checksum = checksum.And(b, checksumMask)
-
-	b.Div(b, big.NewInt(0).Add(checksumMask, bigOne))
-
-	// The entropy is the underlying bytes of the big.Int. Any upper bytes of
-	// all 0's are not returned so we pad the beginning of the slice with empty
-	// bytes if necessary.
-	entropy := b.Bytes()
-	entropy = padByteSlice(entropy, len(mnemonicSlice)/3*4)
-
-	// Generate the checksum and compare with the one we got from the mnemonic.
-	entropyChecksumBytes := computeChecksum(entropy)
-	entropyChecksum := big.NewInt(int64(entropyChecksumBytes[0]))
+	return entropy, nil
+}
 
-	if l := len(mnemonicSlice); l != 24 {
-		checksumShift := wordLengthChecksumShiftMapping[l]
-		entropyChecksum.Div(entropyChecksum, checksumShift)
+// EntropyFromMnemonicSecret is like EntropyFromMnemonic for a mnemonic held
+// in a byte slice, and returns the entropy in a Secret. The error of an
+// unknown word does not include the word.
+func EntropyFromMnemonicSecret(lang string, mnemonic []byte) (*Secret, error) {
+	lang = checkLanguage(lang)
+	if lang == "error" {
+		return nil, ErrInvalidLanguage
+	}
+	words := bytes.Fields(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > maxWords {
+		return nil, ErrInvalidMnemonic
 	}
 
-	if checksum.Cmp(entropyChecksum) != 0 {
+	entropy := NewSecret(len(words) / 3 * 4)
+	unknown, valid := decodeWords(lang, words, entropy.Bytes())
+	if unknown >= 0 || !valid {
+		entropy.Wipe()
+		if unknown >= 0 {
+			return nil, ErrInvalidMnemonic
+		}
 		return nil, ErrChecksumIncorrect
 	}
 
@@ -237,52 +237,51 @@ func NewRandMnemonic(lang string, mnemonicSize int) (string, error) {
 // the given entropy.
 // If the provide entropy is invalid, an error will be returned.
 func NewMnemonic(lang string, entropy []byte) (string, error) {
-	lang = checkLanguage(lang)
-	if lang == "error" {
-		return "", ErrInvalidLanguage
-	}
-
-	// Compute some lengths for convenience.
-	entropyBitLength := len(entropy) * 8
-	checksumBitLength := entropyBitLength / 32
-	sentenceLength := (entropyBitLength + checksumBitLength) / 11
-
-	// Validate that the requested size is supported.
-	err := validateEntropyBitSize(entropyBitLength)
+	secret, err := NewMnemonicSecret(lang, entropy)
 	if err != nil {
 		return "", err
 	}
+	defer secret.Wipe()
 
-	// Add checksum to entropy.
-	entropy = addChecksum(entropy)
-
-	// Break entropy up into sentenceLength chunks of 11 bits.
-	// For each word AND mask the rightmost 11 bits and find the word at that index.
-	// Then bit shift entropy 11 bits right and repeat.
-	// Add to the last empty slot so we can work with LSBs instead of MSB.
+	return string(secret.Bytes()), nil
+}
 
-	// Entropy as an int so we can bit mask without worrying about bytes slices.
-	entropyInt := new(big.Int).SetBytes(entropy)
+// NewMnemonicSecret is like NewMnemonic but returns the mnemonic in a Secret.
+func NewMnemonicSecret(lang string, entropy []byte) (*Secret, error) {
+	lang = checkLanguage(lang)
+	if lang == "error" {
+		return nil, ErrInvalidLanguage
+	}
 
-	// Slice to hold words in.
-	words := make([]string, sentenceLength)
+	// Validate that the requested size is supported.
+	if err := validateEntropyBitSize(len(entropy) * 8); err != nil {
+		return nil, err
+	}
 
-	// Throw away big.Int for AND masking.
-	word := big.NewInt(0)
+	var indexes [maxWords]int
+	defer wipeIndexes(indexes[:])
 
-	for i := sentenceLength - 1; i >= 0; i-- {
-		// Get 11 right most bits and bit shift 11 to the right for next time.
-		word.And(entropyInt, last11BitsMask)
-		entropyInt.Div(entropyInt, shift11BitsMask)
+	words := indexes[:len(entropy)*3/4]
+	encodeIndexes(entropy, words)
 
-		// Get the bytes representing the 11 bits as a 2 byte slice.
-		wordBytes := padByteSlice(word.Bytes(), 2)
+	// The mnemonic is written into a buffer of its exact size, as growing a
+	// buffer would leave copies behind.
+	list := wordList[lang]
+	size := len(words) - 1
+	for _, index := range words {
+		size += len(list[index])
+	}
 
-		// Convert bytes to an index and add that word to the list.
-		words[i] = wordList[lang][binary.BigEndian.Uint16(wordBytes)]
+	mnemonic := NewSecret(size)
+	b := mnemonic.Bytes()[:0]
+	for i, index := range words {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, list[index]...)
 	}
 
-	return strings.Join(words, " "), nil
+	return mnemonic, nil
 }
 
 // MnemonicToByteArray takes a mnemonic string and turns it into a byte array
@@ -325,7 +324,151 @@ func NewSeedWithErrorChecking(lang string, mnemonic string, password string) ([]
 // NewSeed creates a hashed seed output given a provided string and password.
 // No checking is performed to validate that the string provided is a valid mnemonic.
 func NewSeed(mnemonic string, password string) []byte {
-	return pbkdf2.Key(norm.NFKD.Bytes([]byte(mnemonic)), norm.NFKD.Bytes([]byte("mnemonic"+password)), 2048, 64, sha512.New)
+	deriver := NewSeedDeriver(mnemonic)
+	defer deriver.Wipe()
+
+	return deriver.Seed(password)
+}
+
+// NewSeedSecret is like NewSeed for a mnemonic and a password held in byte
+// slices, and returns the seed in a Secret.
+func NewSeedSecret(mnemonic []byte, password []byte) *Secret {
+	deriver := newSeedDeriver(mnemonic)
+	defer deriver.Wipe()
+
+	return deriver.SeedSecret(password)
+}
+
+// NewSeedContext is like NewSeed but stops and returns the context error as
+// soon as ctx is done.
+func NewSeedContext(ctx context.Context, mnemonic string, password string) ([]byte, error) {
+	deriver := NewSeedDeriver(mnemonic)
+	defer deriver.Wipe()
+
+	return deriver.SeedContext(ctx, password)
+}
+
+// NewSeedsContext derives the seeds of a mnemonic with each of the given
+// passwords on at most workers goroutines, or GOMAXPROCS if workers is not
+// positive. The seeds are returned in the order of the passwords. It stops and
+// returns the context error as soon as ctx is done.
+func NewSeedsContext(ctx context.Context, mnemonic string, passwords []string, workers int) ([][]byte, error) {
+	b := []byte(mnemonic)
+	defer wipe(b)
+
+	return newSeedsContext(ctx, b, passwords, workers)
+}
+
+func newSeedsContext(ctx context.Context, mnemonic []byte, passwords []string, workers int) ([][]byte, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(passwords) {
+		workers = len(passwords)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	seeds := make([][]byte, len(passwords))
+	indexes := make(chan int)
+	errs := make(chan error, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deriver := newSeedDeriver(mnemonic)
+			defer deriver.Wipe()
+			for i := range indexes {
+				seed, err := deriver.SeedContext(ctx, passwords[i])
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+				seeds[i] = seed
+			}
+		}()
+	}
+
+feed:
+	for i := range passwords {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	err := ctx.Err()
+	select {
+	case err = <-errs:
+	default:
+	}
+	if err != nil {
+		for _, seed := range seeds {
+			wipe(seed)
+		}
+		return nil, err
+	}
+
+	return seeds, nil
+}
+
+// Fingerprint returns the 4 byte BIP32 master key fingerprint, the first
+// bytes of the HASH160 of the master public key, of the given mnemonic and
+// password. Comparing it against a stored fingerprint detects a mistyped
+// password without revealing the seed.
+func Fingerprint(lang string, mnemonic string, password string) ([]byte, error) {
+	seed, err := NewSeedWithErrorChecking(lang, mnemonic, password)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(seed)
+
+	return seedFingerprint(seed)
+}
+
+// seedFingerprint returns the master key fingerprint of a seed.
+func seedFingerprint(seed []byte) ([]byte, error) {
+	master, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(master.Key)
+	defer wipe(master.ChainCode)
+
+	return bip32.Hash160(master.PublicKeyBytes())[:4], nil
+}
+
+// IsFingerprintMatching reports whether the master key fingerprint of the given
+// mnemonic and password equals the given hex fingerprint, such as "73c5da0a".
+// An error is returned if the mnemonic or fingerprint is invalid.
+func IsFingerprintMatching(lang string, mnemonic string, password string, fingerprint string) (bool, error) {
+	return isFingerprintMatching(fingerprint, func() ([]byte, error) {
+		return Fingerprint(lang, mnemonic, password)
+	})
+}
+
+// isFingerprintMatching reports whether a hex fingerprint is valid and equals
+// the fingerprint computed by actualFingerprint, which is only called for a
+// valid fingerprint.
+func isFingerprintMatching(fingerprint string, actualFingerprint func() ([]byte, error)) (bool, error) {
+	expected, err := hex.DecodeString(fingerprint)
+	if err != nil || len(expected) != 4 {
+		return false, ErrInvalidFingerprint
+	}
+
+	actual, err := actualFingerprint()
+	if err != nil {
+		return false, err
+	}
+
+	return compareByteSlices(expected, actual), nil
 }
 
 // IsMnemonicValid attempts to verify that the provided mnemonic is valid.
@@ -336,39 +479,65 @@ func IsMnemonicValid(lang string, mnemonic string) bool {
 	return err == nil
 }
 
-// Appends to data the first (len(data) / 32)bits of the result of sha256(data)
+// addChecksum returns data followed by the first (len(data) / 4) bits of
+// sha256(data), right aligned in len(data)+1 bytes.
 // Currently only supports data up to 32 bytes.
 func addChecksum(data []byte) []byte {
-	// Get first byte of sha256
-	hash := computeChecksum(data)
-	firstChecksumByte := hash[0]
+	checksum := sha256.Sum256(data)
+	defer wipe(checksum[:])
 
-	// len() is in bytes so we divide by 4
+	// Shift the data left by the checksum length and set the checksum bits in
+	// the freed rightmost bits. Shifting a byte by 8 bits clears it, which
+	// covers the 8 bit checksum of 32 bytes.
 	checksumBitLength := uint(len(data) / 4)
+	result := make([]byte, len(data)+1)
+	result[0] = data[0] >> (8 - checksumBitLength)
+	for i := 1; i < len(data); i++ {
+		result[i] = data[i-1]<<checksumBitLength | data[i]>>(8-checksumBitLength)
+	}
+	result[len(data)] = data[len(data)-1]<<checksumBitLength | checksum[0]>>(8-checksumBitLength)
 
-	// For each bit of check sum we want we shift the data one the left
-	// and then set the (new) right most bit equal to checksum bit at that index
-	// staring from the left
-	dataBigInt := new(big.Int).SetBytes(data)
-
-	for i := uint(0); i < checksumBitLength; i++ {
-		// Bit shift 1 left
-		dataBigInt.Mul(dataBigInt, bigTwo)
+	return result
+}
 
-		// Set rightmost bit if leftmost checksum bit is set
-		if firstChecksumByte&(1<<(7-i)) > 0 {
-			dataBigInt.Or(dataBigInt, bigOne)
-		}
+// encodeIndexes stores in indexes the 11 bit word indexes of entropy followed
+// by its checksum.
+func encodeIndexes(entropy []byte, indexes []int) {
+	var packed [packedLength]byte
+	checksum := sha256.Sum256(entropy)
+	defer wipe(packed[:])
+	defer wipe(checksum[:])
+
+	copy(packed[:], entropy)
+	packed[len(entropy)] = checksum[0]
+
+	for i := range indexes {
+		offset := i * 11
+		window := uint32(packed[offset/8])<<16 | uint32(packed[offset/8+1])<<8 | uint32(packed[offset/8+2])
+		indexes[i] = int(window>>(13-offset%8)) & 0x7ff
 	}
-
-	return dataBigInt.Bytes()
 }
 
-func computeChecksum(data []byte) []byte {
-	hasher := sha256.New()
-	_, _ = hasher.Write(data) // This error is guaranteed to be nil
+// decodeIndexes stores in entropy the entropy encoded by 11 bit word indexes
+// and reports whether the checksum bits following it are correct.
+func decodeIndexes(indexes []int, entropy []byte) bool {
+	var packed [packedLength]byte
+	defer wipe(packed[:])
+
+	for i, index := range indexes {
+		offset := i * 11
+		window := uint32(index) << (13 - offset%8)
+		packed[offset/8] |= byte(window >> 16)
+		packed[offset/8+1] |= byte(window >> 8)
+		packed[offset/8+2] |= byte(window)
+	}
+	copy(entropy, packed[:len(entropy)])
+
+	checksum := sha256.Sum256(entropy)
+	defer wipe(checksum[:])
 
-	return hasher.Sum(nil)
+	mask := byte(0xff << (8 - uint(len(entropy)/4)))
+	return subtle.ConstantTimeByteEq(packed[len(entropy)]&mask, checksum[0]&mask) == 1
 }
 
 // validateEntropyBitSize ensures that entropy is the correct size for being a
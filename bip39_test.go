@@ -2,12 +2,16 @@ package bip39
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/decen-one/go-bip39/assert"
+	"github.com/decen-one/go-bip39/bip32"
 	"github.com/decen-one/go-bip39/wordlist"
 )
 
@@ -113,6 +117,92 @@ func TestIsMnemonicValid(t *testing.T) {
 	}
 }
 
+func TestFingerprint(t *testing.T) {
+	for _, vector := range fingerprintVectors() {
+		fingerprint, err := Fingerprint(vector.lang, vector.mnemonic, vector.password)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.fingerprint, hex.EncodeToString(fingerprint))
+
+		ok, err := IsFingerprintMatching(vector.lang, vector.mnemonic, vector.password, vector.fingerprint)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		ok, err = IsFingerprintMatching(vector.lang, vector.mnemonic, vector.password+"x", vector.fingerprint)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	}
+
+	for _, vector := range testVectors() {
+		seed, _ := hex.DecodeString(vector.seed)
+		master, err := bip32.NewMasterKey(seed)
+		assert.Nil(t, err)
+
+		fingerprint, err := Fingerprint(vector.lang, vector.mnemonic, vector.password)
+		assert.Nil(t, err)
+		assert.Equal(t, master.Fingerprint(), binary.BigEndian.Uint32(fingerprint))
+	}
+
+	for _, vector := range badMnemonicSentences() {
+		_, err := Fingerprint(vector.lang, vector.mnemonic, "")
+		assert.NotNil(t, err)
+	}
+
+	mnemonic := fingerprintVectors()[0].mnemonic
+	for _, fingerprint := range []string{"", "73c5da", "73c5da0a00", "73c5da0z"} {
+		_, err := IsFingerprintMatching("english", mnemonic, "", fingerprint)
+		assert.Equal(t, ErrInvalidFingerprint, err)
+	}
+}
+
+func TestNewSeedContext(t *testing.T) {
+	for _, vector := range testVectors() {
+		seed, err := NewSeedContext(context.Background(), vector.mnemonic, vector.password)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.seed, hex.EncodeToString(seed))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewSeedContext(ctx, fingerprintVectors()[0].mnemonic, "")
+	assert.Equal(t, context.Canceled, err)
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = NewSeedContext(ctx, fingerprintVectors()[0].mnemonic, "")
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestNewSeedsContext(t *testing.T) {
+	mnemonic := fingerprintVectors()[0].mnemonic
+	passwords := make([]string, 50)
+	for i := range passwords {
+		passwords[i] = fmt.Sprintf("password %d", i)
+	}
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		seeds, err := NewSeedsContext(context.Background(), mnemonic, passwords, workers)
+		assert.Nil(t, err)
+		assert.Equal(t, len(passwords), len(seeds))
+
+		for i, password := range passwords {
+			assert.EqualByteSlices(t, NewSeed(mnemonic, password), seeds[i])
+		}
+	}
+
+	seeds, err := NewSeedsContext(context.Background(), mnemonic, nil, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(seeds))
+
+	// Cancelling stops the workers before all the seeds are derived.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(time.Millisecond)
+		cancel()
+	}()
+	_, err = NewSeedsContext(ctx, mnemonic, make([]string, 100000), 2)
+	assert.Equal(t, context.Canceled, err)
+}
+
 func TestMnemonicToByteArrayWithRawIsEqualToEntropyFromMnemonic(t *testing.T) {
 	for _, vector := range testVectors() {
 		rawEntropy, err := MnemonicToByteArray(vector.lang, vector.mnemonic, true)
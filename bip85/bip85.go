@@ -0,0 +1,227 @@
+// Package bip85 implements BIP85 deterministic entropy derived from a BIP32
+// root key, and the BIP39, HEX, WIF, XPRV and password applications on top of
+// it.
+package bip85
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/base58"
+	"github.com/decen-one/go-bip39/bip32"
+)
+
+// Application numbers registered by BIP85.
+const (
+	AppBIP39     = 39
+	AppWIF       = 2
+	AppXPRV      = 32
+	AppHex       = 128169
+	AppPWDBase64 = 707764
+	AppPWDBase85 = 707785
+)
+
+// purpose is the first, hardened, index of all BIP85 derivation paths.
+const purpose = 83696968
+
+var (
+	// ErrInvalidRootKey is returned when the root key is not a private key.
+	ErrInvalidRootKey = errors.New("Root key must be a private key")
+
+	// ErrInvalidMnemonicSize is returned when trying to derive a mnemonic with
+	// a word count that is not registered.
+	ErrInvalidMnemonicSize = errors.New("Mnemonic length must be 12, 18 or 24 words")
+
+	// ErrInvalidLength is returned when trying to derive hex entropy or a
+	// password of an invalid length.
+	ErrInvalidLength = errors.New("Invalid length")
+
+	// ErrInvalidLanguage is returned when trying to use a language that is not
+	// registered.
+	ErrInvalidLanguage = bip39.ErrInvalidLanguage
+)
+
+// languageCodes maps bip39 languages to their BIP85 language code.
+var languageCodes = map[string]uint32{
+	"english":             0,
+	"japanese":            1,
+	"korean":              2,
+	"spanish":             3,
+	"chinese-simplified":  4,
+	"chinese-traditional": 5,
+	"french":              6,
+	"italian":             7,
+	"czech":               8,
+	"portuguese":          9,
+}
+
+// NewRootKey creates the BIP32 root key used to derive BIP85 entropy from a
+// mnemonic and passphrase.
+func NewRootKey(lang string, mnemonic string, passphrase string) (*bip32.Key, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(lang, mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return bip32.NewMasterKey(seed)
+}
+
+// DeriveEntropy derives the 64 bytes of entropy at the given path, which must
+// start with m/83696968'.
+func DeriveEntropy(root *bip32.Key, path string) ([]byte, error) {
+	if !root.IsPrivate {
+		return nil, ErrInvalidRootKey
+	}
+
+	key, err := root.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha512.New, []byte("bip-entropy-from-k"))
+	_, _ = mac.Write(key.Key)
+
+	return mac.Sum(nil), nil
+}
+
+// deriveApplication derives the entropy at m/83696968'/app'/indexes'...
+func deriveApplication(root *bip32.Key, app uint32, indexes ...uint32) ([]byte, error) {
+	path := fmt.Sprintf("m/%d'/%d'", purpose, app)
+	for _, index := range indexes {
+		if index >= bip32.FirstHardenedIndex {
+			return nil, ErrInvalidLength
+		}
+		path += fmt.Sprintf("/%d'", index)
+	}
+
+	return DeriveEntropy(root, path)
+}
+
+// NewMnemonic derives a child mnemonic of the given language and size at
+// m/83696968'/39'/language'/words'/index'.
+func NewMnemonic(root *bip32.Key, lang string, words int, index uint32) (string, error) {
+	code, ok := languageCodes[strings.ToLower(lang)]
+	if !ok {
+		return "", ErrInvalidLanguage
+	}
+	if words != 12 && words != 18 && words != 24 {
+		return "", ErrInvalidMnemonicSize
+	}
+
+	entropy, err := deriveApplication(root, AppBIP39, code, uint32(words), index)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(lang, entropy[:words*4/3])
+}
+
+// NewHex derives numBytes bytes of hex encoded entropy at
+// m/83696968'/128169'/numBytes'/index'. numBytes must be within [16, 64].
+func NewHex(root *bip32.Key, numBytes int, index uint32) (string, error) {
+	if numBytes < 16 || numBytes > 64 {
+		return "", ErrInvalidLength
+	}
+
+	entropy, err := deriveApplication(root, AppHex, uint32(numBytes), index)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(entropy[:numBytes]), nil
+}
+
+// NewWIF derives a compressed mainnet private key in wallet import format at
+// m/83696968'/2'/index'.
+func NewWIF(root *bip32.Key, index uint32) (string, error) {
+	entropy, err := deriveApplication(root, AppWIF, index)
+	if err != nil {
+		return "", err
+	}
+
+	payload := append([]byte{0x80}, entropy[:32]...)
+	payload = append(payload, 0x01)
+
+	return base58.CheckEncode(payload), nil
+}
+
+// NewXPRV derives an extended private root key at m/83696968'/32'/index'.
+// The first 32 bytes of entropy are the chain code and the last 32 bytes the
+// private key.
+func NewXPRV(root *bip32.Key, index uint32) (*bip32.Key, error) {
+	entropy, err := deriveApplication(root, AppXPRV, index)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &bip32.Key{
+		Version:   bip32.MainnetPrivateVersion,
+		ChainCode: entropy[:32],
+		Key:       entropy[32:],
+		IsPrivate: true,
+	}
+
+	// Make sure the private key is within the curve order.
+	if _, err := bip32.Deserialize(key.Serialize()); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// NewPasswordBase64 derives a base64 password of the given length at
+// m/83696968'/707764'/length'/index'. length must be within [20, 86].
+func NewPasswordBase64(root *bip32.Key, length int, index uint32) (string, error) {
+	if length < 20 || length > 86 {
+		return "", ErrInvalidLength
+	}
+
+	entropy, err := deriveApplication(root, AppPWDBase64, uint32(length), index)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(entropy)[:length], nil
+}
+
+// NewPasswordBase85 derives a base85 password of the given length at
+// m/83696968'/707785'/length'/index'. length must be within [10, 80].
+func NewPasswordBase85(root *bip32.Key, length int, index uint32) (string, error) {
+	if length < 10 || length > 80 {
+		return "", ErrInvalidLength
+	}
+
+	entropy, err := deriveApplication(root, AppPWDBase85, uint32(length), index)
+	if err != nil {
+		return "", err
+	}
+
+	return encodeBase85(entropy)[:length], nil
+}
+
+// base85Alphabet is the RFC 1924 alphabet used by Python's base64.b85encode.
+const base85Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+
+// encodeBase85 encodes data, whose length must be a multiple of 4, with the
+// RFC 1924 alphabet.
+func encodeBase85(data []byte) string {
+	encoded := make([]byte, 0, len(data)/4*5)
+	for i := 0; i+4 <= len(data); i += 4 {
+		value := uint32(data[i])<<24 | uint32(data[i+1])<<16 | uint32(data[i+2])<<8 | uint32(data[i+3])
+
+		var chunk [5]byte
+		for j := 4; j >= 0; j-- {
+			chunk[j] = base85Alphabet[value%85]
+			value /= 85
+		}
+		encoded = append(encoded, chunk[:]...)
+	}
+
+	return string(encoded)
+}
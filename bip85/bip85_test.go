@@ -0,0 +1,123 @@
+package bip85
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+	"github.com/decen-one/go-bip39/bip32"
+)
+
+// Test vectors from https://github.com/bitcoin/bips/blob/master/bip-0085.mediawiki
+const rootKey = "xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb"
+
+func testRootKey(t *testing.T) *bip32.Key {
+	root, err := bip32.B58Deserialize(rootKey)
+	assert.Nil(t, err)
+
+	return root
+}
+
+func TestDeriveEntropy(t *testing.T) {
+	root := testRootKey(t)
+
+	for _, vector := range []struct {
+		path    string
+		entropy string
+	}{
+		{"m/83696968'/0'/0'", "efecfbccffea313214232d29e71563d941229afb4338c21f9517c41aaa0d16f00b83d2a09ef747e7a64e8e2bd5a14869e693da66ce94ac2da570ab7ee48618f7"},
+		{"m/83696968'/0'/1'", "70c6e3e8ebee8dc4c0dbba66076819bb8c09672527c4277ca8729532ad711872218f826919f6b67218adde99018a6df9095ab2b58d803b5b93ec9802085a690e"},
+	} {
+		entropy, err := DeriveEntropy(root, vector.path)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.entropy, hex.EncodeToString(entropy))
+	}
+
+	_, err := DeriveEntropy(root.PublicKey(), "m/83696968'/0'/0'")
+	assert.Equal(t, ErrInvalidRootKey, err)
+}
+
+func TestNewMnemonic(t *testing.T) {
+	root := testRootKey(t)
+
+	for _, vector := range []struct {
+		words    int
+		mnemonic string
+	}{
+		{12, "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose"},
+		{18, "near account window bike charge season chef number sketch tomorrow excuse sniff circle vital hockey outdoor supply token"},
+		{24, "puppy ocean match cereal symbol another shed magic wrap hammer bulb intact gadget divorce twin tonight reason outdoor destroy simple truth cigar social volcano"},
+	} {
+		mnemonic, err := NewMnemonic(root, "english", vector.words, 0)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.mnemonic, mnemonic)
+	}
+
+	_, err := NewMnemonic(root, "english", 15, 0)
+	assert.Equal(t, ErrInvalidMnemonicSize, err)
+
+	_, err = NewMnemonic(root, "klingon", 12, 0)
+	assert.Equal(t, ErrInvalidLanguage, err)
+
+	// Each language uses its own derivation path.
+	english, _ := NewMnemonic(root, "english", 12, 0)
+	for lang := range languageCodes {
+		mnemonic, err := NewMnemonic(root, lang, 24, 0)
+		assert.Nil(t, err)
+		if lang != "english" {
+			assert.False(t, mnemonic == english)
+		}
+	}
+}
+
+func TestNewRootKey(t *testing.T) {
+	root, err := NewRootKey("english", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	assert.Nil(t, err)
+	assert.True(t, root.IsPrivate)
+
+	_, err = NewRootKey("english", "abandon abandon abandon", "")
+	assert.NotNil(t, err)
+}
+
+func TestNewWIF(t *testing.T) {
+	wif, err := NewWIF(testRootKey(t), 0)
+	assert.Nil(t, err)
+	assert.EqualString(t, "Kzyv4uF39d4Jrw2W7UryTHwZr1zQVNk4dAFyqE6BuMrMh1Za7uhp", wif)
+}
+
+func TestNewXPRV(t *testing.T) {
+	key, err := NewXPRV(testRootKey(t), 0)
+	assert.Nil(t, err)
+	assert.EqualString(t, "xprv9s21ZrQH143K2srSbCSg4m4kLvPMzcWydgmKEnMmoZUurYuBuYG46c6P71UGXMzmriLzCCBvKQWBUv3vPB3m1SATMhp3uEjXHJ42jFg7myX", key.String())
+}
+
+func TestNewHex(t *testing.T) {
+	root := testRootKey(t)
+
+	entropy, err := NewHex(root, 64, 0)
+	assert.Nil(t, err)
+	assert.EqualString(t, "492db4698cf3b73a5a24998aa3e9d7fa96275d85724a91e71aa2d645442f878555d078fd1f1f67e368976f04137b1f7a0d19232136ca50c44614af72b5582a5c", entropy)
+
+	for _, size := range []int{15, 65} {
+		_, err = NewHex(root, size, 0)
+		assert.Equal(t, ErrInvalidLength, err)
+	}
+}
+
+func TestNewPassword(t *testing.T) {
+	root := testRootKey(t)
+
+	password, err := NewPasswordBase64(root, 21, 0)
+	assert.Nil(t, err)
+	assert.EqualString(t, "dKLoepugzdVJvdL56ogNV", password)
+
+	password, err = NewPasswordBase85(root, 12, 0)
+	assert.Nil(t, err)
+	assert.EqualString(t, "_s`{TW89)i4`", password)
+
+	_, err = NewPasswordBase64(root, 19, 0)
+	assert.Equal(t, ErrInvalidLength, err)
+
+	_, err = NewPasswordBase85(root, 81, 0)
+	assert.Equal(t, ErrInvalidLength, err)
+}
@@ -0,0 +1,255 @@
+// Package cardano implements the Cardano root key generation schemes described
+// in CIP-3 (Icarus, Ledger and Trezor) and BIP32-Ed25519 child key derivation.
+package cardano
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"filippo.io/edwards25519"
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/slip10"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// FirstHardenedIndex is the index of the first hardened child key.
+const FirstHardenedIndex = slip10.FirstHardenedIndex
+
+// Scheme selects how the root key is generated from a mnemonic.
+type Scheme int
+
+const (
+	// Icarus derives the root key from the mnemonic entropy. It is used by
+	// Yoroi, Daedalus and most software wallets.
+	Icarus Scheme = iota
+
+	// Ledger derives the root key from the BIP39 seed as Ledger devices do.
+	Ledger
+
+	// Trezor behaves as Icarus except for 24 word mnemonics, where the
+	// checksum byte is included with the entropy as Trezor devices do.
+	Trezor
+)
+
+var (
+	// ErrInvalidScheme is returned when trying to use an unknown scheme.
+	ErrInvalidScheme = errors.New("Invalid root key scheme")
+
+	// ErrHardenedPublicDerivation is returned when trying to derive a hardened
+	// child from a public key.
+	ErrHardenedPublicDerivation = errors.New("Can not derive hardened child from public key")
+)
+
+// XPrv is a BIP32-Ed25519 extended private key.
+type XPrv struct {
+	// Key is the 64 byte extended secret kL || kR.
+	Key       []byte
+	ChainCode []byte
+}
+
+// XPub is a BIP32-Ed25519 extended public key.
+type XPub struct {
+	PublicKey []byte
+	ChainCode []byte
+}
+
+// NewMasterKey creates the root key of the given mnemonic and passphrase
+// using the given scheme.
+func NewMasterKey(scheme Scheme, lang string, mnemonic string, passphrase string) (*XPrv, error) {
+	switch scheme {
+	case Icarus:
+		entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+		if err != nil {
+			return nil, err
+		}
+		return NewIcarusMasterKey(entropy, passphrase), nil
+
+	case Ledger:
+		seed, err := bip39.NewSeedWithErrorChecking(lang, mnemonic, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return NewLedgerMasterKey(seed), nil
+
+	case Trezor:
+		entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+		if err != nil {
+			return nil, err
+		}
+		if len(strings.Fields(mnemonic)) == 24 {
+			entropy, _ = bip39.MnemonicToByteArray(lang, mnemonic)
+		}
+		return NewIcarusMasterKey(entropy, passphrase), nil
+
+	default:
+		return nil, ErrInvalidScheme
+	}
+}
+
+// NewIcarusMasterKey creates the Icarus root key from the given entropy, as
+// returned by bip39.EntropyFromMnemonic, and passphrase.
+func NewIcarusMasterKey(entropy []byte, passphrase string) *XPrv {
+	data := pbkdf2.Key([]byte(passphrase), entropy, 4096, 96, sha512.New)
+
+	// Clear the lowest 3 bits and the highest 3rd bit, set the highest 2nd bit.
+	data[0] &= 0xf8
+	data[31] &= 0x1f
+	data[31] |= 0x40
+
+	return &XPrv{Key: data[:64], ChainCode: data[64:]}
+}
+
+// NewLedgerMasterKey creates the Ledger root key from the given seed, as
+// returned by bip39.NewSeed.
+func NewLedgerMasterKey(seed []byte) *XPrv {
+	modifier := []byte("ed25519 seed")
+
+	chainCode := hmac.New(sha256.New, modifier)
+	_, _ = chainCode.Write([]byte{1})
+	_, _ = chainCode.Write(seed)
+
+	// Hash repeatedly until the highest 3rd bit of kL is cleared.
+	I := hmacSHA512(modifier, seed)
+	for I[31]&0x20 != 0 {
+		I = hmacSHA512(modifier, I)
+	}
+
+	// Clear the lowest 3 bits and the highest bit, set the highest 2nd bit.
+	I[0] &= 0xf8
+	I[31] &= 0x7f
+	I[31] |= 0x40
+
+	return &XPrv{Key: I, ChainCode: chainCode.Sum(nil)}
+}
+
+// Bytes returns the 96 byte encoding kL || kR || chain code of k.
+func (k *XPrv) Bytes() []byte {
+	return append(append([]byte{}, k.Key...), k.ChainCode...)
+}
+
+// PublicKey returns the 32 byte ed25519 public key of k.
+func (k *XPrv) PublicKey() []byte {
+	return scalarBaseMult(k.Key[:32]).Bytes()
+}
+
+// XPub returns the extended public key of k.
+func (k *XPrv) XPub() *XPub {
+	return &XPub{PublicKey: k.PublicKey(), ChainCode: k.ChainCode}
+}
+
+// NewChildKey derives the child key at the given index using the V2
+// BIP32-Ed25519 scheme. Indexes at or above FirstHardenedIndex produce
+// hardened children.
+func (k *XPrv) NewChildKey(index uint32) *XPrv {
+	var data []byte
+	if index >= FirstHardenedIndex {
+		data = append([]byte{0}, k.Key...)
+	} else {
+		data = append([]byte{2}, k.PublicKey()...)
+	}
+	data = binary.LittleEndian.AppendUint32(data, index)
+
+	Z := hmacSHA512(k.ChainCode, data)
+	data[0]++
+	chainCode := hmacSHA512(k.ChainCode, data)[32:]
+
+	key := make([]byte, 64)
+	add28Mul8(key[:32], k.Key[:32], Z[:28])
+	add256(key[32:], k.Key[32:], Z[32:])
+
+	return &XPrv{Key: key, ChainCode: chainCode}
+}
+
+// Derive follows the given path, such as "m/1852'/1815'/0'/0/0", from k.
+func (k *XPrv) Derive(path string) (*XPrv, error) {
+	indexes, err := slip10.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := k
+	for _, index := range indexes {
+		key = key.NewChildKey(index)
+	}
+
+	return key, nil
+}
+
+// Bytes returns the 64 byte encoding public key || chain code of k.
+func (k *XPub) Bytes() []byte {
+	return append(append([]byte{}, k.PublicKey...), k.ChainCode...)
+}
+
+// NewChildKey derives the non-hardened child public key at the given index.
+func (k *XPub) NewChildKey(index uint32) (*XPub, error) {
+	if index >= FirstHardenedIndex {
+		return nil, ErrHardenedPublicDerivation
+	}
+
+	A, err := new(edwards25519.Point).SetBytes(k.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	data := append([]byte{2}, k.PublicKey...)
+	data = binary.LittleEndian.AppendUint32(data, index)
+
+	Z := hmacSHA512(k.ChainCode, data)
+	data[0]++
+	chainCode := hmacSHA512(k.ChainCode, data)[32:]
+
+	// A' = A + 8*ZL*B
+	zl8 := make([]byte, 32)
+	add28Mul8(zl8, make([]byte, 32), Z[:28])
+	A.Add(A, scalarBaseMult(zl8))
+
+	return &XPub{PublicKey: A.Bytes(), ChainCode: chainCode}, nil
+}
+
+// scalarBaseMult returns k*B for the little endian 256 bit integer k, which
+// is not necessarily reduced modulo the group order.
+func scalarBaseMult(k []byte) *edwards25519.Point {
+	wide := make([]byte, 64)
+	copy(wide, k)
+	s, _ := edwards25519.NewScalar().SetUniformBytes(wide) // err is always nil for 64 bytes
+
+	return new(edwards25519.Point).ScalarBaseMult(s)
+}
+
+// add28Mul8 sets out to x + 8*y where x is a 32 byte and y a 28 byte little
+// endian integer, truncating the result to 32 bytes.
+func add28Mul8(out, x, y []byte) {
+	var carry uint16
+	for i := 0; i < 28; i++ {
+		r := uint16(x[i]) + uint16(y[i])<<3 + carry
+		out[i] = byte(r)
+		carry = r >> 8
+	}
+	for i := 28; i < 32; i++ {
+		r := uint16(x[i]) + carry
+		out[i] = byte(r)
+		carry = r >> 8
+	}
+}
+
+// add256 sets out to x + y modulo 2^256 where x and y are 32 byte little
+// endian integers.
+func add256(out, x, y []byte) {
+	var carry uint16
+	for i := 0; i < 32; i++ {
+		r := uint16(x[i]) + uint16(y[i]) + carry
+		out[i] = byte(r)
+		carry = r >> 8
+	}
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	_, _ = mac.Write(data)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,146 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// Test vectors from https://github.com/cardano-foundation/CIPs/tree/master/CIP-0003
+var testVectors = []struct {
+	scheme     Scheme
+	mnemonic   string
+	passphrase string
+	masterKey  string
+}{
+	{
+		scheme:     Icarus,
+		mnemonic:   "eight country switch draw meat scout mystery blade tip drift useless good keep usage title",
+		passphrase: "",
+		masterKey:  "c065afd2832cd8b087c4d9ab7011f481ee1e0721e78ea5dd609f3ab3f156d245d176bd8fd4ec60b4731c3918a2a72a0226c0cd119ec35b47e4d55884667f552a23f7fdcd4a10c6cd2c7393ac61d877873e248f417634aa3d812af327ffe9d620",
+	},
+	{
+		scheme:     Icarus,
+		mnemonic:   "eight country switch draw meat scout mystery blade tip drift useless good keep usage title",
+		passphrase: "foo",
+		masterKey:  "70531039904019351e1afb361cd1b312a4d0565d4ff9f8062d38acf4b15cce41d7b5738d9c893feea55512a3004acb0d222c35d3e3d5cde943a15a9824cbac59443cf67e589614076ba01e354b1a432e0e6db3b59e37fc56b5fb0222970a010e",
+	},
+	{
+		scheme:     Ledger,
+		mnemonic:   "recall grace sport punch exhibit mad harbor stand obey short width stem awkward used stairs wool ugly trap season stove worth toward congress jaguar",
+		passphrase: "",
+		masterKey:  "a08cf85b564ecf3b947d8d4321fb96d70ee7bb760877e371899b14e2ccf88658104b884682b57efd97decbb318a45c05a527b9cc5c2f64f7352935a049ceea60680d52308194ccef2a18e6812b452a5815fbd7f5babc083856919aaf668fe7e4",
+	},
+	{
+		scheme:     Ledger,
+		mnemonic:   "correct cherry mammal bubble want mandate polar hazard crater better craft exotic choice fun tourist census gap lottery neglect address glow carry old business",
+		passphrase: "",
+		masterKey:  "587c6774357ecbf840d4db6404ff7af016dace0400769751ad2abfc77b9a3844cc71702520ef1a4d1b68b91187787a9b8faab0a9bb6b160de541b6ee62469901fc0beda0975fe4763beabd83b7051a5fd5cbce5b88e82c4bbaca265014e524bd",
+	},
+	{
+		scheme:     Ledger,
+		mnemonic:   "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		passphrase: "foo",
+		masterKey:  "f053a1e752de5c26197b60f032a4809f08bb3e5d90484fe42024be31efcba7578d914d3ff992e21652fee6a4d99f6091006938fac2c0c0f9d2de0ba64b754e92a4f3723f23472077aa4cd4dd8a8a175dba07ea1852dad1cf268c61a2679c3890",
+	},
+}
+
+func TestNewMasterKey(t *testing.T) {
+	for _, vector := range testVectors {
+		key, err := NewMasterKey(vector.scheme, "english", vector.mnemonic, vector.passphrase)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.masterKey, hex.EncodeToString(key.Bytes()))
+	}
+}
+
+func TestNewMasterKeyTrezor(t *testing.T) {
+	// Trezor matches Icarus except for 24 word mnemonics.
+	mnemonic := testVectors[0].mnemonic
+	icarus, err := NewMasterKey(Icarus, "english", mnemonic, "foo")
+	assert.Nil(t, err)
+	trezor, err := NewMasterKey(Trezor, "english", mnemonic, "foo")
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, icarus.Bytes(), trezor.Bytes())
+
+	mnemonic = testVectors[2].mnemonic
+	icarus, err = NewMasterKey(Icarus, "english", mnemonic, "")
+	assert.Nil(t, err)
+	trezor, err = NewMasterKey(Trezor, "english", mnemonic, "")
+	assert.Nil(t, err)
+	assert.False(t, bytes.Equal(icarus.Bytes(), trezor.Bytes()))
+
+	entropy, err := bip39.MnemonicToByteArray("english", mnemonic)
+	assert.Nil(t, err)
+	assert.Equal(t, 33, len(entropy))
+	assert.EqualByteSlices(t, NewIcarusMasterKey(entropy, "").Bytes(), trezor.Bytes())
+}
+
+func TestNewMasterKeyInvalid(t *testing.T) {
+	_, err := NewMasterKey(Icarus, "english", "abandon abandon abandon", "")
+	assert.Equal(t, bip39.ErrInvalidMnemonic, err)
+
+	_, err = NewMasterKey(Ledger, "klingon", testVectors[2].mnemonic, "")
+	assert.Equal(t, bip39.ErrInvalidLanguage, err)
+
+	_, err = NewMasterKey(Scheme(42), "english", testVectors[0].mnemonic, "")
+	assert.Equal(t, ErrInvalidScheme, err)
+}
+
+func TestNewChildKey(t *testing.T) {
+	root, err := NewMasterKey(Icarus, "english", testVectors[0].mnemonic, "")
+	assert.Nil(t, err)
+
+	account, err := root.Derive("m/1852'/1815'/0'")
+	assert.Nil(t, err)
+
+	// Non-hardened private and public derivation must agree.
+	for _, path := range []string{"m/0/0", "m/0/1", "m/1/0", "m/2/0"} {
+		key, err := account.Derive(path)
+		assert.Nil(t, err)
+
+		xpub := account.XPub()
+		for _, segment := range strings.Split(path, "/")[1:] {
+			index := uint32(segment[0] - '0')
+			xpub, err = xpub.NewChildKey(index)
+			assert.Nil(t, err)
+		}
+
+		assert.EqualByteSlices(t, key.XPub().Bytes(), xpub.Bytes())
+	}
+
+	// The extended secret must keep the BIP32-Ed25519 clamping.
+	key, err := account.Derive("m/0/0")
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0), key.Key[0]&0x07)
+
+	_, err = account.XPub().NewChildKey(FirstHardenedIndex)
+	assert.Equal(t, ErrHardenedPublicDerivation, err)
+}
+
+func TestNewChildKeyVectors(t *testing.T) {
+	// The hardened child 0x80000000 of the ed25519-bip32 crate tests.
+	data, _ := hex.DecodeString("f8a29231ee38d6c5bf715d5bac21c750577aa3798b22d79d65bf97d6fadea15adcd1ee1abdf78bd4be64731a12deb94d3671784112eb6f364b871851fd1c9a247384db9ad6003bbd08b3b1ddc0d07a597293ff85e961bf252b331262eddfad0d")
+	key := &XPrv{Key: data[:64], ChainCode: data[64:]}
+	assert.EqualString(t, "60d399da83ef80d8d4f8d223239efdc2b8fef387e1b5219137ffb4e8fbdea15adc9366b7d003af37c11396de9a83734e30e05e851efa32745c9cd7b42712c890608763770eddf77248ab652984b21b849760d1da74a6f5bd633ce41adceef07a",
+		hex.EncodeToString(key.NewChildKey(FirstHardenedIndex).Bytes()))
+
+	// The payment key of the CIP-19 test vectors, whose bech32 encoding is
+	// addr_vk1w0l2sr2zgfm26ztc6nl9xy8ghsk5sh6ldwemlpmp9xylzy4dtf7st80zhd.
+	root, err := NewMasterKey(Icarus, "english", "test walk nut penalty hip pave soap entry language right filter choice", "")
+	assert.Nil(t, err)
+	account, err := root.Derive("m/1852'/1815'/0'")
+	assert.Nil(t, err)
+	payment, err := account.Derive("m/0/0")
+	assert.Nil(t, err)
+	assert.EqualString(t, "73fea80d424276ad0978d4fe5310e8bc2d485f5f6bb3bf87612989f112ad5a7d", hex.EncodeToString(payment.PublicKey()))
+
+	xpub, err := account.XPub().NewChildKey(0)
+	assert.Nil(t, err)
+	xpub, err = xpub.NewChildKey(0)
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, payment.PublicKey(), xpub.PublicKey)
+}
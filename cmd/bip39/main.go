@@ -0,0 +1,374 @@
+// Command bip39 generates, validates and converts BIP39 mnemonics.
+//
+// Usage:
+//
+//	bip39 generate [-lang english] [-words 24] [-json]
+//	bip39 validate [-lang english] [-json]
+//	bip39 seed [-lang english] [-passphrase-stdin] [-json]
+//	bip39 entropy [-lang english] [-json]
+//	bip39 from-entropy [-lang english] [-json]
+//	bip39 translate [-lang english] -to japanese [-json]
+//
+// Mnemonics, entropy and passphrases are secrets and are never taken from the
+// command line, where other users could see them. They are read from stdin,
+// one per line, or typed at a prompt with echo disabled when stdin is a
+// terminal. The seed command uses an empty passphrase unless
+// -passphrase-stdin is given.
+//
+// With -json the result, or the error, is printed as a JSON object. The exit
+// status tells errors apart:
+//
+//	0  success
+//	1  input or output error
+//	2  invalid usage
+//	3  invalid language
+//	4  invalid mnemonic or unknown word
+//	5  incorrect checksum
+//	6  invalid entropy or mnemonic size
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/decen-one/go-bip39"
+	"golang.org/x/term"
+)
+
+// Exit statuses.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitInvalidLanguage
+	exitInvalidMnemonic
+	exitChecksumIncorrect
+	exitInvalidSize
+)
+
+// errUsage is returned for invalid command lines, after printing the usage.
+var errUsage = errors.New("Invalid usage")
+
+// errInvalidEntropy is returned when the entropy is not hex encoded.
+var errInvalidEntropy = errors.New("Entropy must be hex encoded")
+
+// exitCodes maps errors to exit statuses.
+var exitCodes = map[error]int{
+	errUsage:                      exitUsage,
+	bip39.ErrInvalidLanguage:      exitInvalidLanguage,
+	bip39.ErrInvalidMnemonic:      exitInvalidMnemonic,
+	bip39.ErrChecksumIncorrect:    exitChecksumIncorrect,
+	bip39.ErrEntropyLengthInvalid: exitInvalidSize,
+	bip39.ErrMnemonicSizeInvalid:  exitInvalidSize,
+	errInvalidEntropy:             exitInvalidSize,
+}
+
+func exitCode(err error) int {
+	for target, code := range exitCodes {
+		if errors.Is(err, target) {
+			return code
+		}
+	}
+
+	return exitError
+}
+
+const usage = `Usage: bip39 <command> [flags]
+
+Commands:
+  generate      generate a random mnemonic
+  validate      check a mnemonic
+  seed          derive the seed of a mnemonic
+  entropy       print the entropy of a mnemonic in hex
+  from-entropy  print the mnemonic of hex entropy
+  translate     print a mnemonic in another language
+
+Secrets are read from stdin, or from a prompt with echo disabled when stdin is
+a terminal. Run bip39 <command> -h for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// command is a subcommand, writing its result as text or as a JSON object.
+type command func(in *secretReader, flags *commonFlags) (text string, object interface{}, err error)
+
+type commonFlags struct {
+	lang            string
+	words           int
+	to              string
+	passphraseStdin bool
+	json            bool
+}
+
+// run runs the command line args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	commands := map[string]command{
+		"generate":     generate,
+		"validate":     validate,
+		"seed":         seed,
+		"entropy":      entropy,
+		"from-entropy": fromEntropy,
+		"translate":    translate,
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	f := &commonFlags{}
+	fs := flag.NewFlagSet("bip39 "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&f.lang, "lang", "english", "language of the mnemonic")
+	fs.BoolVar(&f.json, "json", false, "print the result as JSON")
+	switch args[0] {
+	case "generate":
+		fs.IntVar(&f.words, "words", 24, "number of words, 12, 15, 18, 21 or 24")
+	case "seed":
+		fs.BoolVar(&f.passphraseStdin, "passphrase-stdin", false, "read the passphrase from stdin after the mnemonic")
+	case "translate":
+		fs.StringVar(&f.to, "to", "", "language to translate to")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "bip39: unexpected argument %q, secrets are read from stdin\n", fs.Arg(0))
+		return exitUsage
+	}
+
+	text, object, err := cmd(newSecretReader(stdin, stderr), f)
+	if err != nil {
+		code := exitCode(err)
+		if f.json {
+			writeJSON(stdout, map[string]interface{}{"error": err.Error(), "code": code})
+		} else {
+			fmt.Fprintf(stderr, "bip39: %v\n", err)
+		}
+		return code
+	}
+
+	if f.json {
+		writeJSON(stdout, object)
+	} else {
+		fmt.Fprintln(stdout, text)
+	}
+
+	return exitOK
+}
+
+func writeJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(v) // The values are maps and structs of strings, numbers and booleans
+}
+
+// secretReader reads secrets from a terminal with echo disabled, or one per
+// line from a pipe or a file.
+type secretReader struct {
+	terminal *os.File
+	lines    *bufio.Reader
+	prompts  io.Writer
+}
+
+func newSecretReader(stdin io.Reader, prompts io.Writer) *secretReader {
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return &secretReader{terminal: f, prompts: prompts}
+	}
+
+	return &secretReader{lines: bufio.NewReader(stdin), prompts: prompts}
+}
+
+// read returns the next secret, without its line ending. The caller should
+// wipe it after use.
+func (r *secretReader) read(prompt string) ([]byte, error) {
+	if r.terminal != nil {
+		fmt.Fprintf(r.prompts, "%s: ", prompt)
+		defer fmt.Fprintln(r.prompts)
+
+		return term.ReadPassword(int(r.terminal.Fd()))
+	}
+
+	line, err := r.lines.ReadBytes('\n')
+	if err == io.EOF && len(line) > 0 {
+		err = nil
+	}
+	if err == io.EOF {
+		return nil, fmt.Errorf("Missing %s on stdin", strings.ToLower(prompt))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r")), nil
+}
+
+// readMnemonic reads and checks a mnemonic.
+func (r *secretReader) readMnemonic(lang string) (bip39.Mnemonic, error) {
+	b, err := r.read("Mnemonic")
+	if err != nil {
+		return bip39.Mnemonic{}, err
+	}
+	defer wipe(b)
+
+	return bip39.ParseMnemonicBytes(lang, b)
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+type mnemonicResult struct {
+	Mnemonic string `json:"mnemonic"`
+	Language string `json:"language"`
+	Words    int    `json:"words"`
+}
+
+func newMnemonicResult(m bip39.Mnemonic) (string, interface{}, error) {
+	words := m.Reveal()
+	return words, mnemonicResult{Mnemonic: words, Language: m.Language(), Words: len(bytes.Fields([]byte(words)))}, nil
+}
+
+func generate(in *secretReader, f *commonFlags) (string, interface{}, error) {
+	m, err := bip39.RandMnemonic(f.lang, f.words)
+	if err != nil {
+		return "", nil, err
+	}
+	defer m.Wipe()
+
+	return newMnemonicResult(m)
+}
+
+func validate(in *secretReader, f *commonFlags) (string, interface{}, error) {
+	m, err := in.readMnemonic(f.lang)
+	if err != nil {
+		return "", nil, err
+	}
+	defer m.Wipe()
+
+	e, err := m.Entropy()
+	if err != nil {
+		return "", nil, err
+	}
+	defer e.Wipe()
+
+	words := e.Len() * 3 / 4
+	return fmt.Sprintf("Valid %d word %s mnemonic", words, m.Language()), map[string]interface{}{
+		"valid":    true,
+		"language": m.Language(),
+		"words":    words,
+	}, nil
+}
+
+func seed(in *secretReader, f *commonFlags) (string, interface{}, error) {
+	m, err := in.readMnemonic(f.lang)
+	if err != nil {
+		return "", nil, err
+	}
+	defer m.Wipe()
+
+	var passphrase []byte
+	if f.passphraseStdin {
+		if passphrase, err = in.read("Passphrase"); err != nil {
+			return "", nil, err
+		}
+		defer wipe(passphrase)
+	}
+
+	deriver := m.Deriver()
+	defer deriver.Wipe()
+	s := deriver.SeedSecret(passphrase)
+	defer s.Wipe()
+
+	seed := hex.EncodeToString(s.Bytes())
+	return seed, map[string]string{"seed": seed}, nil
+}
+
+func entropy(in *secretReader, f *commonFlags) (string, interface{}, error) {
+	m, err := in.readMnemonic(f.lang)
+	if err != nil {
+		return "", nil, err
+	}
+	defer m.Wipe()
+
+	e, err := m.Entropy()
+	if err != nil {
+		return "", nil, err
+	}
+	defer e.Wipe()
+
+	entropy := hex.EncodeToString(e.Bytes())
+	return entropy, map[string]string{"entropy": entropy}, nil
+}
+
+func fromEntropy(in *secretReader, f *commonFlags) (string, interface{}, error) {
+	line, err := in.read("Entropy")
+	if err != nil {
+		return "", nil, err
+	}
+	defer wipe(line)
+
+	line = bytes.TrimSpace(line)
+	e := bip39.NewSecret(hex.DecodedLen(len(line)))
+	defer e.Wipe()
+	if _, err := hex.Decode(e.Bytes(), line); err != nil {
+		return "", nil, errInvalidEntropy
+	}
+
+	m, err := bip39.MnemonicFromEntropy(f.lang, e.Bytes())
+	if err != nil {
+		return "", nil, err
+	}
+	defer m.Wipe()
+
+	return newMnemonicResult(m)
+}
+
+func translate(in *secretReader, f *commonFlags) (string, interface{}, error) {
+	if f.to == "" {
+		return "", nil, fmt.Errorf("%w: -to is required", errUsage)
+	}
+	if _, err := bip39.GetWordList(f.to); err != nil {
+		return "", nil, err
+	}
+
+	m, err := in.readMnemonic(f.lang)
+	if err != nil {
+		return "", nil, err
+	}
+	defer m.Wipe()
+
+	e, err := m.Entropy()
+	if err != nil {
+		return "", nil, err
+	}
+	defer e.Wipe()
+
+	translated, err := bip39.MnemonicFromEntropy(f.to, e.Bytes())
+	if err != nil {
+		return "", nil, err
+	}
+	defer translated.Wipe()
+
+	return newMnemonicResult(translated)
+}
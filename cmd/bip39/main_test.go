@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+)
+
+const (
+	mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	// The Japanese wordlist is NFD normalized.
+	japanese = "あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あいこくしん あおそ\u3099ら"
+)
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return stdout.String(), stderr.String(), code
+}
+
+func TestCommands(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		stdin  string
+		stdout string
+	}{
+		{[]string{"validate"}, mnemonic + "\n", "Valid 12 word english mnemonic\n"},
+		{[]string{"entropy"}, mnemonic, "00000000000000000000000000000000\n"},
+		{[]string{"from-entropy"}, "00000000000000000000000000000000\r\n", mnemonic + "\n"},
+		{[]string{"seed"}, mnemonic + "\n", "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4\n"},
+		{[]string{"seed", "-passphrase-stdin"}, mnemonic + "\nTREZOR\n", "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04\n"},
+		{[]string{"translate", "-to", "japanese"}, mnemonic, japanese + "\n"},
+		{[]string{"translate", "-lang", "japanese", "-to", "english"}, japanese + "\n", mnemonic + "\n"},
+	} {
+		stdout, stderr, code := runCommand(t, test.stdin, test.args...)
+		assert.Equal(t, exitOK, code)
+		assert.EqualString(t, test.stdout, stdout)
+		assert.EqualString(t, "", stderr)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	stdout, _, code := runCommand(t, "", "generate", "-words", "15", "-lang", "spanish", "-json")
+	assert.Equal(t, exitOK, code)
+
+	var result mnemonicResult
+	assert.Nil(t, json.Unmarshal([]byte(stdout), &result))
+	assert.EqualString(t, "spanish", result.Language)
+	assert.Equal(t, 15, result.Words)
+
+	stdout, _, code = runCommand(t, result.Mnemonic, "validate", "-lang", "spanish")
+	assert.Equal(t, exitOK, code)
+	assert.EqualString(t, "Valid 15 word spanish mnemonic\n", stdout)
+
+	stdout, _, code = runCommand(t, "", "generate")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, 24, len(strings.Fields(stdout)))
+}
+
+func TestJSON(t *testing.T) {
+	stdout, _, code := runCommand(t, mnemonic, "validate", "-json")
+	assert.Equal(t, exitOK, code)
+	assert.EqualString(t, `{"language":"english","valid":true,"words":12}`+"\n", stdout)
+
+	stdout, _, code = runCommand(t, mnemonic, "entropy", "-json")
+	assert.Equal(t, exitOK, code)
+	assert.EqualString(t, `{"entropy":"00000000000000000000000000000000"}`+"\n", stdout)
+
+	stdout, _, code = runCommand(t, "00000000000000000000000000000000", "from-entropy", "-json")
+	assert.Equal(t, exitOK, code)
+	assert.EqualString(t, `{"mnemonic":"`+mnemonic+`","language":"english","words":12}`+"\n", stdout)
+
+	stdout, _, code = runCommand(t, strings.Replace(mnemonic, "about", "abandon", 1), "validate", "-json")
+	assert.Equal(t, exitChecksumIncorrect, code)
+	assert.EqualString(t, `{"code":5,"error":"Checksum incorrect"}`+"\n", stdout)
+}
+
+func TestErrors(t *testing.T) {
+	for _, test := range []struct {
+		args  []string
+		stdin string
+		code  int
+	}{
+		{nil, "", exitUsage},
+		{[]string{"unknown"}, "", exitUsage},
+		{[]string{"validate", mnemonic}, "", exitUsage},
+		{[]string{"validate", "-unknown"}, mnemonic, exitUsage},
+		{[]string{"translate"}, mnemonic, exitUsage},
+		{[]string{"validate"}, "", exitError},
+		{[]string{"seed", "-passphrase-stdin"}, mnemonic, exitError},
+		{[]string{"validate", "-lang", "klingon"}, mnemonic, exitInvalidLanguage},
+		{[]string{"translate", "-to", "klingon"}, mnemonic, exitInvalidLanguage},
+		{[]string{"generate", "-lang", "klingon"}, "", exitInvalidLanguage},
+		{[]string{"validate"}, "abandon abandon", exitInvalidMnemonic},
+		{[]string{"entropy"}, strings.Replace(mnemonic, "about", "secretword", 1), exitInvalidMnemonic},
+		{[]string{"validate"}, strings.Replace(mnemonic, "about", "abandon", 1), exitChecksumIncorrect},
+		{[]string{"generate", "-words", "13"}, "", exitInvalidSize},
+		{[]string{"from-entropy"}, "0000", exitInvalidSize},
+		{[]string{"from-entropy"}, "not hex", exitInvalidSize},
+	} {
+		stdout, stderr, code := runCommand(t, test.stdin, test.args...)
+		assert.Equal(t, test.code, code)
+		assert.EqualString(t, "", stdout)
+		assert.True(t, stderr != "")
+
+		// Errors never echo the secrets read from stdin.
+		assert.False(t, strings.Contains(stderr, "secretword"))
+	}
+
+	_, _, code := runCommand(t, "", "generate", "-h")
+	assert.Equal(t, exitOK, code)
+}
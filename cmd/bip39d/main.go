@@ -0,0 +1,140 @@
+// Command bip39d serves BIP39 mnemonic operations to local services over
+// JSON-RPC 2.0.
+//
+// Usage:
+//
+//	bip39d -socket /run/bip39d/bip39d.sock [-max-request-size 65536]
+//
+// The daemon listens on a Unix domain socket, which only its user can
+// connect to, and answers JSON-RPC 2.0 calls, or batches of calls, sent with
+// HTTP POST to any path. All the params are named:
+//
+//	validate        {mnemonic, language}             -> {valid, reason}
+//	generate        {language, words}                -> {mnemonic, language, words}
+//	toEntropy       {mnemonic, language}             -> {entropy}
+//	fromEntropy     {entropy, language}              -> {mnemonic, language, words}
+//	detectLanguage  {mnemonic}                       -> {languages}
+//	fingerprint     {mnemonic, language, passphrase} -> {fingerprint}
+//
+// The language defaults to english, and entropy is hex encoded. Errors of the
+// package are reported with the server error codes -32001 invalid language,
+// -32002 invalid mnemonic, -32003 incorrect checksum, -32004 invalid size and
+// -32005 invalid entropy.
+//
+// Requests larger than -max-request-size are rejected. Only the method, the
+// error code and the duration of the calls are logged, never their params or
+// results.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run runs the daemon until it is interrupted, and returns the exit status.
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bip39d", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	socket := fs.String("socket", "", "path of the Unix domain socket to listen on")
+	maxRequestSize := fs.Int64("max-request-size", 64<<10, "maximum size of a request body in bytes")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *socket == "" || *maxRequestSize <= 0 || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	logger := log.New(stderr, "bip39d: ", log.LstdFlags)
+	listener, err := listen(*socket)
+	if err != nil {
+		logger.Print(err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := serve(ctx, listener, newServer(*maxRequestSize, logger), logger); err != nil {
+		logger.Print(err)
+		return 1
+	}
+
+	return 0
+}
+
+// listen listens on a Unix domain socket at path, replacing a stale socket,
+// and makes it accessible to the user only.
+func listen(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// The socket is created with the permissions of the umask, so it is
+	// accessible to other users until the chmod below without this. The
+	// umask is process wide, but nothing else creates files yet.
+	umask := setUmask(0o177)
+	listener, err := net.Listen("unix", path)
+	setUmask(umask)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// serve serves handler on listener until ctx is done, then waits for the
+// calls in progress.
+func serve(ctx context.Context, listener net.Listener, handler http.Handler, logger *log.Logger) error {
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       time.Minute,
+		MaxHeaderBytes:    8 << 10,
+		ErrorLog:          logger,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/decen-one/go-bip39"
+)
+
+// JSON-RPC 2.0 error codes, and the server errors of the service.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+
+	codeInvalidLanguage   = -32001
+	codeInvalidMnemonic   = -32002
+	codeChecksumIncorrect = -32003
+	codeInvalidSize       = -32004
+	codeInvalidEntropy    = -32005
+)
+
+// maxBatchSize is the number of calls of a batch request.
+const maxBatchSize = 100
+
+// languages are the languages tried by detectLanguage.
+var languages = []string{
+	"english",
+	"chinese-simplified",
+	"chinese-traditional",
+	"czech",
+	"french",
+	"italian",
+	"japanese",
+	"korean",
+	"portuguese",
+	"spanish",
+}
+
+// errInvalidEntropy is returned when the entropy is not hex encoded.
+var errInvalidEntropy = errors.New("Entropy must be hex encoded")
+
+// errorCodes maps errors to JSON-RPC error codes.
+var errorCodes = map[error]int{
+	bip39.ErrInvalidLanguage:      codeInvalidLanguage,
+	bip39.ErrInvalidMnemonic:      codeInvalidMnemonic,
+	bip39.ErrChecksumIncorrect:    codeChecksumIncorrect,
+	bip39.ErrEntropyLengthInvalid: codeInvalidSize,
+	bip39.ErrMnemonicSizeInvalid:  codeInvalidSize,
+	errInvalidEntropy:             codeInvalidEntropy,
+}
+
+type request struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// newError returns the JSON-RPC error of a method error.
+func newError(err error) *rpcError {
+	var rpcErr *rpcError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	for target, code := range errorCodes {
+		if errors.Is(err, target) {
+			return &rpcError{Code: code, Message: target.Error()}
+		}
+	}
+
+	// Other errors may carry parameters, such as an unknown word.
+	return &rpcError{Code: codeInternalError, Message: "Internal error"}
+}
+
+// method is a JSON-RPC method, which decodes its params and returns a result.
+type method func(params json.RawMessage) (interface{}, error)
+
+var methods = map[string]method{
+	"validate":       validate,
+	"generate":       generate,
+	"toEntropy":      toEntropy,
+	"fromEntropy":    fromEntropy,
+	"detectLanguage": detectLanguage,
+	"fingerprint":    fingerprint,
+}
+
+// server serves JSON-RPC 2.0 requests over HTTP POST. It logs the method,
+// the error code and the duration of each call, but never their params or
+// results.
+type server struct {
+	maxRequestSize int64
+	logger         *log.Logger
+}
+
+func newServer(maxRequestSize int64, logger *log.Logger) *server {
+	return &server{maxRequestSize: maxRequestSize, logger: logger}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxRequestSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeResponse(w, http.StatusRequestEntityTooLarge, response{
+				Version: "2.0",
+				Error:   &rpcError{Code: codeInvalidRequest, Message: "Request too large"},
+				ID:      json.RawMessage("null"),
+			})
+			return
+		}
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	defer wipe(body)
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		if resp := s.call(trimmed); resp != nil {
+			writeResponse(w, http.StatusOK, resp)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(trimmed, &batch); err != nil {
+		writeResponse(w, http.StatusOK, errorResponse(nil, codeParseError, "Parse error"))
+		return
+	}
+	if len(batch) == 0 || len(batch) > maxBatchSize {
+		writeResponse(w, http.StatusOK, errorResponse(nil, codeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	responses := make([]*response, 0, len(batch))
+	for _, call := range batch {
+		if resp := s.call(call); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeResponse(w, http.StatusOK, responses)
+}
+
+// call runs a single call and returns its response, or nil for a
+// notification.
+func (s *server) call(data []byte) *response {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return errorResponse(nil, codeParseError, "Parse error")
+		}
+		return errorResponse(nil, codeInvalidRequest, "Invalid request")
+	}
+	if req.Version != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "Invalid request")
+	}
+
+	start := time.Now()
+	resp := &response{Version: "2.0", ID: req.ID}
+	if m, ok := methods[req.Method]; !ok {
+		resp.Error = &rpcError{Code: codeMethodNotFound, Message: "Method not found"}
+	} else if result, err := m(req.Params); err != nil {
+		resp.Error = newError(err)
+	} else {
+		resp.Result = result
+	}
+
+	// Unknown method names are not logged, as they could be anything.
+	name, code := req.Method, 0
+	if resp.Error != nil {
+		code = resp.Error.Code
+	}
+	if code == codeMethodNotFound {
+		name = "unknown"
+	}
+	s.logger.Printf("method=%s code=%d duration=%v", name, code, time.Since(start))
+
+	if req.ID == nil {
+		return nil
+	}
+
+	return resp
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	return &response{Version: "2.0", Error: &rpcError{Code: code, Message: message}, ID: id}
+}
+
+func writeResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) // The client may be gone, there is nobody to tell
+}
+
+// decodeParams decodes the named params of a call into v, rejecting unknown
+// fields.
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: "Invalid params"}
+	}
+
+	return nil
+}
+
+type mnemonicParams struct {
+	Mnemonic string `json:"mnemonic"`
+	Language string `json:"language"`
+}
+
+func (p *mnemonicParams) parse() (bip39.Mnemonic, error) {
+	if p.Language == "" {
+		p.Language = "english"
+	}
+
+	return bip39.ParseMnemonic(p.Language, p.Mnemonic)
+}
+
+type mnemonicResult struct {
+	Mnemonic string `json:"mnemonic"`
+	Language string `json:"language"`
+	Words    int    `json:"words"`
+}
+
+func newMnemonicResult(m bip39.Mnemonic) mnemonicResult {
+	words := m.Reveal()
+	return mnemonicResult{Mnemonic: words, Language: m.Language(), Words: len(strings.Fields(words))}
+}
+
+type validateResult struct {
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// validate reports whether a mnemonic is valid, and why not. The reason never
+// includes the mnemonic.
+func validate(params json.RawMessage) (interface{}, error) {
+	var p mnemonicParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	m, err := p.parse()
+	if errors.Is(err, bip39.ErrInvalidLanguage) {
+		return nil, err
+	}
+	if err != nil {
+		return validateResult{Reason: newError(err).Message}, nil
+	}
+	m.Wipe()
+
+	return validateResult{Valid: true}, nil
+}
+
+// generate returns a random mnemonic.
+func generate(params json.RawMessage) (interface{}, error) {
+	p := struct {
+		Language string `json:"language"`
+		Words    int    `json:"words"`
+	}{Language: "english", Words: 24}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	m, err := bip39.RandMnemonic(p.Language, p.Words)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Wipe()
+
+	return newMnemonicResult(m), nil
+}
+
+// toEntropy returns the hex entropy of a mnemonic.
+func toEntropy(params json.RawMessage) (interface{}, error) {
+	var p mnemonicParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	m, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	defer m.Wipe()
+
+	e, err := m.Entropy()
+	if err != nil {
+		return nil, err
+	}
+	defer e.Wipe()
+
+	return map[string]string{"entropy": hex.EncodeToString(e.Bytes())}, nil
+}
+
+// fromEntropy returns the mnemonic of hex entropy.
+func fromEntropy(params json.RawMessage) (interface{}, error) {
+	p := struct {
+		Entropy  string `json:"entropy"`
+		Language string `json:"language"`
+	}{Language: "english"}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	e := bip39.NewSecret(hex.DecodedLen(len(p.Entropy)))
+	defer e.Wipe()
+	if _, err := hex.Decode(e.Bytes(), []byte(p.Entropy)); err != nil {
+		return nil, errInvalidEntropy
+	}
+
+	m, err := bip39.MnemonicFromEntropy(p.Language, e.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer m.Wipe()
+
+	return newMnemonicResult(m), nil
+}
+
+// detectLanguage returns the languages in which a mnemonic is valid. Some
+// words are in several wordlists, so a mnemonic may be valid in more than one
+// language.
+func detectLanguage(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Mnemonic string `json:"mnemonic"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	valid := []string{}
+	for _, lang := range languages {
+		m, err := bip39.ParseMnemonic(lang, p.Mnemonic)
+		if err == nil {
+			valid = append(valid, lang)
+			m.Wipe()
+		}
+	}
+
+	return map[string][]string{"languages": valid}, nil
+}
+
+// fingerprint returns the hex master key fingerprint of a mnemonic and a
+// passphrase.
+func fingerprint(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		mnemonicParams
+		Passphrase string `json:"passphrase"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	m, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	defer m.Wipe()
+
+	fp, err := m.Fingerprint(p.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"fingerprint": hex.EncodeToString(fp)}, nil
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
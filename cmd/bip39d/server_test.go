@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+)
+
+const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// testServer is a daemon served in process on a Unix domain socket.
+type testServer struct {
+	*httptest.Server
+	client *http.Client
+	logs   *bytes.Buffer
+}
+
+func newTestServer(t *testing.T, maxRequestSize int64) *testServer {
+	listener, err := listen(filepath.Join(t.TempDir(), "bip39d.sock"))
+	assert.Nil(t, err)
+
+	logs := &bytes.Buffer{}
+	srv := httptest.NewUnstartedServer(newServer(maxRequestSize, log.New(logs, "", 0)))
+	srv.Listener.Close()
+	srv.Listener = listener
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", listener.Addr().String())
+		},
+	}}
+
+	return &testServer{Server: srv, client: client, logs: logs}
+}
+
+// post sends body and returns the status and the response body.
+func (s *testServer) post(t *testing.T, body string) (int, string) {
+	resp, err := s.client.Post("http://bip39d/", "application/json", strings.NewReader(body))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+
+	return resp.StatusCode, string(b)
+}
+
+// call calls method with params, decodes its result into result and returns
+// its error, or an error with a zero code.
+func (s *testServer) call(t *testing.T, method string, params interface{}, result interface{}) rpcError {
+	p, err := json.Marshal(params)
+	assert.Nil(t, err)
+	req, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": json.RawMessage(p), "id": 1})
+	assert.Nil(t, err)
+
+	status, body := s.post(t, string(req))
+	assert.Equal(t, http.StatusOK, status)
+
+	var resp struct {
+		Version string          `json:"jsonrpc"`
+		Result  json.RawMessage `json:"result"`
+		Error   *rpcError       `json:"error"`
+		ID      int             `json:"id"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(body), &resp))
+	assert.EqualString(t, "2.0", resp.Version)
+	assert.Equal(t, 1, resp.ID)
+	if resp.Error != nil {
+		return *resp.Error
+	}
+	assert.Nil(t, json.Unmarshal(resp.Result, result))
+
+	return rpcError{}
+}
+
+func TestMethods(t *testing.T) {
+	s := newTestServer(t, 64<<10)
+
+	var valid validateResult
+	assert.Equal(t, 0, s.call(t, "validate", map[string]string{"mnemonic": mnemonic}, &valid).Code)
+	assert.True(t, valid.Valid)
+
+	assert.Equal(t, 0, s.call(t, "validate", map[string]string{"mnemonic": strings.Replace(mnemonic, "about", "abandon", 1)}, &valid).Code)
+	assert.False(t, valid.Valid)
+	assert.EqualString(t, "Checksum incorrect", valid.Reason)
+
+	var generated mnemonicResult
+	assert.Equal(t, 0, s.call(t, "generate", map[string]interface{}{"language": "spanish", "words": 15}, &generated).Code)
+	assert.EqualString(t, "spanish", generated.Language)
+	assert.Equal(t, 15, generated.Words)
+	assert.Equal(t, 0, s.call(t, "validate", map[string]string{"mnemonic": generated.Mnemonic, "language": "spanish"}, &valid).Code)
+	assert.True(t, valid.Valid)
+
+	var entropy map[string]string
+	assert.Equal(t, 0, s.call(t, "toEntropy", map[string]string{"mnemonic": mnemonic}, &entropy).Code)
+	assert.EqualString(t, "00000000000000000000000000000000", entropy["entropy"])
+
+	var fromEntropy mnemonicResult
+	assert.Equal(t, 0, s.call(t, "fromEntropy", map[string]string{"entropy": entropy["entropy"]}, &fromEntropy).Code)
+	assert.EqualString(t, mnemonic, fromEntropy.Mnemonic)
+	assert.Equal(t, 12, fromEntropy.Words)
+
+	var languages map[string][]string
+	assert.Equal(t, 0, s.call(t, "detectLanguage", map[string]string{"mnemonic": generated.Mnemonic}, &languages).Code)
+	assert.EqualStringsSlices(t, []string{"spanish"}, languages["languages"])
+	assert.Equal(t, 0, s.call(t, "detectLanguage", map[string]string{"mnemonic": "not a mnemonic"}, &languages).Code)
+	assert.EqualStringsSlices(t, []string{}, languages["languages"])
+
+	var fingerprint map[string]string
+	assert.Equal(t, 0, s.call(t, "fingerprint", map[string]string{"mnemonic": mnemonic}, &fingerprint).Code)
+	assert.EqualString(t, "73c5da0a", fingerprint["fingerprint"])
+}
+
+func TestErrors(t *testing.T) {
+	s := newTestServer(t, 64<<10)
+
+	for _, test := range []struct {
+		method string
+		params interface{}
+		code   int
+	}{
+		{"unknown", nil, codeMethodNotFound},
+		{"validate", []string{mnemonic}, codeInvalidParams},
+		{"validate", map[string]string{"mnemonic": mnemonic, "passphrase": "x"}, codeInvalidParams},
+		{"validate", map[string]string{"mnemonic": mnemonic, "language": "klingon"}, codeInvalidLanguage},
+		{"generate", map[string]interface{}{"words": 13}, codeInvalidSize},
+		{"toEntropy", map[string]string{"mnemonic": "abandon secretword"}, codeInvalidMnemonic},
+		{"toEntropy", map[string]string{"mnemonic": strings.Replace(mnemonic, "about", "abandon", 1)}, codeChecksumIncorrect},
+		{"fromEntropy", map[string]string{"entropy": "not hex"}, codeInvalidEntropy},
+		{"fromEntropy", map[string]string{"entropy": "0000"}, codeInvalidSize},
+		{"fingerprint", map[string]string{"mnemonic": "secretword"}, codeInvalidMnemonic},
+	} {
+		err := s.call(t, test.method, test.params, nil)
+		assert.Equal(t, test.code, err.Code)
+		assert.False(t, strings.Contains(err.Message, "secretword"))
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	s := newTestServer(t, 1024)
+
+	for _, test := range []struct {
+		body     string
+		status   int
+		response string
+	}{
+		{`{"jsonrpc":"2.0","method":"toEntropy","params":{"mnemonic":"` + mnemonic + `"},"id":"a"}`, http.StatusOK,
+			`{"jsonrpc":"2.0","result":{"entropy":"00000000000000000000000000000000"},"id":"a"}`},
+		{`{"jsonrpc":"2.0","method":"validate","params":{"mnemonic":"` + mnemonic + `"}}`, http.StatusNoContent, ``},
+		{`{"jsonrpc":"2.0","method":"validate"`, http.StatusOK,
+			`{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`},
+		{`{"jsonrpc":"1.0","method":"validate","id":2}`, http.StatusOK,
+			`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request"},"id":2}`},
+		{`[]`, http.StatusOK,
+			`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request"},"id":null}`},
+		{`[{"jsonrpc":"2.0","method":"fingerprint","params":{"mnemonic":"` + mnemonic + `","passphrase":""},"id":1},` +
+			`{"jsonrpc":"2.0","method":"validate","params":{}},` +
+			`1]`, http.StatusOK,
+			`[{"jsonrpc":"2.0","result":{"fingerprint":"73c5da0a"},"id":1},` +
+				`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request"},"id":null}]`},
+		{`{"jsonrpc":"2.0","method":"validate","params":{"mnemonic":"` + strings.Repeat("abandon ", 200) + `"},"id":3}`, http.StatusRequestEntityTooLarge,
+			`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Request too large"},"id":null}`},
+	} {
+		status, body := s.post(t, test.body)
+		assert.Equal(t, test.status, status)
+		assert.EqualString(t, test.response, strings.TrimSpace(body))
+	}
+
+	resp, err := s.client.Get("http://bip39d/")
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestLogging(t *testing.T) {
+	s := newTestServer(t, 64<<10)
+
+	var fingerprint map[string]string
+	assert.Equal(t, 0, s.call(t, "fingerprint", map[string]string{"mnemonic": mnemonic, "passphrase": "secretpassphrase"}, &fingerprint).Code)
+	assert.True(t, s.call(t, "toEntropy", map[string]string{"mnemonic": "abandon secretword"}, nil).Code != 0)
+	assert.True(t, s.call(t, mnemonic, nil, nil).Code != 0)
+
+	logs := s.logs.String()
+	assert.True(t, strings.Contains(logs, "method=fingerprint code=0"))
+	assert.True(t, strings.Contains(logs, "method=toEntropy code=-32002"))
+	assert.True(t, strings.Contains(logs, "method=unknown code=-32601"))
+	for _, secret := range []string{"abandon", "secretpassphrase", "secretword", fingerprint["fingerprint"]} {
+		assert.False(t, strings.Contains(logs, secret))
+	}
+}
+
+func TestListen(t *testing.T) {
+	// The umask is restricted while the socket is created, then restored.
+	umask := setUmask(0o022)
+	defer setUmask(umask)
+
+	path := filepath.Join(t.TempDir(), "bip39d.sock")
+	listener, err := listen(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 0o022, setUmask(0o022))
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// A stale socket is replaced, but not another file.
+	listener2, err := listen(path)
+	assert.Nil(t, err)
+	listener2.Close()
+	listener.Close()
+
+	file := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, os.WriteFile(file, nil, 0o600))
+	_, err = listen(file)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package main
+
+func setUmask(mask int) int {
+	return 0
+}
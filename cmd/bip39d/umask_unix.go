@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import "syscall"
+
+// setUmask sets the file mode creation mask of the process and returns the
+// previous one.
+func setUmask(mask int) int {
+	return syscall.Umask(mask)
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/bip32"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Keys read from a terminal in raw mode.
+const (
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyBackspace = 0x08
+	keyTab       = '\t'
+	keyEnter     = '\r'
+	keyNewline   = '\n'
+	keyCtrlU     = 0x15
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+const (
+	// maxSuggestions is the number of completions shown for a prefix.
+	maxSuggestions = 8
+
+	// maxWordLength and maxPassphraseLength bound the typed input.
+	maxWordLength       = 64
+	maxPassphraseLength = 1024
+
+	// clearScreen moves the cursor home and clears the screen and the
+	// scrollback.
+	clearScreen = "\x1b[H\x1b[2J\x1b[3J"
+
+	reverseVideo = "\x1b[7m"
+	resetVideo   = "\x1b[0m"
+)
+
+type state int
+
+const (
+	stateWords state = iota
+	statePassphrase
+	stateResult
+	stateExit
+)
+
+// editor is the state of a mnemonic entry, updated one key at a time. The
+// accepted words are held as wordlist indexes and the word being typed in a
+// byte slice, which are all wiped once the entry is over.
+type editor struct {
+	lang        string
+	list        []string
+	normalized  []string
+	size        int
+	fingerprint bool
+
+	state   state
+	indexes []int
+	current []byte
+	message string
+	result  []string
+	valid   bool
+
+	mnemonic bip39.Mnemonic
+
+	// escape is 1 after an escape key and 2 inside a control sequence, whose
+	// bytes are skipped.
+	escape int
+}
+
+// newEditor returns an editor for a mnemonic of size words in lang, or of
+// any valid size if size is 0.
+func newEditor(lang string, size int, fingerprint bool) (*editor, error) {
+	list, err := bip39.GetWordList(lang)
+	if err != nil {
+		return nil, err
+	}
+	if size != 0 && !isValidSize(size) {
+		return nil, bip39.ErrMnemonicSizeInvalid
+	}
+
+	// Some wordlists are decomposed, while terminals send composed
+	// characters, so words are matched in NFC.
+	normalized := make([]string, len(list))
+	for i, word := range list {
+		normalized[i] = norm.NFC.String(word)
+	}
+
+	return &editor{
+		lang:        lang,
+		list:        list,
+		normalized:  normalized,
+		size:        size,
+		fingerprint: fingerprint,
+		indexes:     make([]int, 0, 24),
+		current:     make([]byte, 0, maxWordLength),
+	}, nil
+}
+
+func isValidSize(size int) bool {
+	return size >= 12 && size <= 24 && size%3 == 0
+}
+
+// matches returns the indexes of the words starting with prefix, and the
+// index of the word equal to prefix or -1.
+func (e *editor) matches(prefix []byte) ([]int, int) {
+	p := norm.NFC.Append(make([]byte, 0, len(prefix)), prefix...)
+	defer wipe(p)
+
+	var indexes []int
+	exact := -1
+	for i, word := range e.normalized {
+		// Comparing with a converted byte slice does not copy it.
+		if len(word) >= len(p) && word[:len(p)] == string(p) {
+			indexes = append(indexes, i)
+			if len(word) == len(p) {
+				exact = i
+			}
+		}
+	}
+
+	return indexes, exact
+}
+
+// key updates the editor with a byte read from the terminal.
+func (e *editor) key(b byte) {
+	switch {
+	case e.escape == 1:
+		e.escape = 0
+		if b == '[' || b == 'O' {
+			e.escape = 2
+		}
+		return
+	case e.escape == 2:
+		if b >= 0x40 && b <= 0x7e {
+			e.escape = 0
+		}
+		return
+	case b == keyEscape:
+		e.escape = 1
+		return
+	case b == keyCtrlC, b == keyCtrlD && len(e.current) == 0:
+		e.state = stateExit
+		return
+	}
+
+	switch e.state {
+	case stateWords:
+		e.wordKey(b)
+	case statePassphrase:
+		e.passphraseKey(b)
+	case stateResult:
+		e.state = stateExit
+	}
+}
+
+func (e *editor) wordKey(b byte) {
+	e.message = ""
+	switch b {
+	case ' ', keyTab:
+		if e.complete(b == keyTab) && len(e.indexes) == e.size {
+			e.check()
+		}
+	case keyEnter, keyNewline:
+		if len(e.current) > 0 && !e.complete(false) {
+			return
+		}
+		e.check()
+	case keyBackspace, keyDelete:
+		if len(e.current) > 0 {
+			e.current = deleteLastRune(e.current)
+		} else if len(e.indexes) > 0 {
+			last := len(e.indexes) - 1
+			e.current = append(e.current, e.normalized[e.indexes[last]]...)
+			e.indexes[last] = 0
+			e.indexes = e.indexes[:last]
+		}
+	case keyCtrlU:
+		wipe(e.current)
+		e.current = e.current[:0]
+	default:
+		if b >= ' ' && len(e.current) < maxWordLength {
+			e.current = append(e.current, b)
+		}
+	}
+}
+
+// complete accepts the typed word if it is a word or the prefix of a single
+// word, or with tab extends it to the longest prefix shared by its matches.
+// It reports whether the word was accepted.
+func (e *editor) complete(tab bool) bool {
+	if len(e.current) == 0 {
+		return false
+	}
+
+	matches, index := e.matches(e.current)
+	if index < 0 && len(matches) == 1 {
+		index = matches[0]
+	}
+
+	switch {
+	case index >= 0:
+		e.indexes = append(e.indexes, index)
+		wipe(e.current)
+		e.current = e.current[:0]
+		return true
+	case len(matches) == 0:
+		e.message = "Unknown word"
+	case tab:
+		prefix := commonPrefix(e.normalized, matches)
+		wipe(e.current)
+		e.current = append(e.current[:0], prefix...)
+	default:
+		e.message = fmt.Sprintf("%d words start with these letters, type more or press Tab", len(matches))
+	}
+
+	return false
+}
+
+// check validates the checksum of the accepted words.
+func (e *editor) check() {
+	if !isValidSize(len(e.indexes)) || (e.size != 0 && len(e.indexes) != e.size) {
+		e.message = "A mnemonic has 12, 15, 18, 21 or 24 words"
+		if e.size != 0 {
+			e.message = fmt.Sprintf("The mnemonic has %d words", e.size)
+		}
+		return
+	}
+
+	words := make([]byte, 0, len(e.indexes)*maxWordLength)
+	defer wipe(words[:cap(words)])
+	for i, index := range e.indexes {
+		if i > 0 {
+			words = append(words, ' ')
+		}
+		words = append(words, e.list[index]...)
+	}
+
+	m, err := bip39.ParseMnemonicBytes(e.lang, words)
+	if err != nil {
+		e.message = err.Error() + ", check the words and edit them with Backspace"
+		return
+	}
+
+	e.mnemonic = m
+	e.result = []string{"Checksum valid"}
+	e.valid = true
+	e.state = stateResult
+	if e.fingerprint {
+		e.current = make([]byte, 0, maxPassphraseLength)
+		e.state = statePassphrase
+	}
+}
+
+func (e *editor) passphraseKey(b byte) {
+	switch b {
+	case keyEnter, keyNewline:
+		e.showFingerprint()
+	case keyBackspace, keyDelete:
+		e.current = deleteLastRune(e.current)
+	case keyCtrlU:
+		wipe(e.current)
+		e.current = e.current[:0]
+	default:
+		if b >= ' ' && len(e.current) < maxPassphraseLength {
+			e.current = append(e.current, b)
+		}
+	}
+}
+
+// showFingerprint derives the master key fingerprint of the mnemonic and the
+// typed passphrase.
+func (e *editor) showFingerprint() {
+	deriver := e.mnemonic.Deriver()
+	defer deriver.Wipe()
+	seed := deriver.SeedSecret(e.current)
+	defer seed.Wipe()
+	wipe(e.current)
+	e.current = e.current[:0]
+
+	e.state = stateResult
+	master, err := bip32.NewMasterKey(seed.Bytes())
+	if err != nil {
+		e.result = append(e.result, "Fingerprint: "+err.Error())
+		return
+	}
+	defer wipe(master.Key)
+	defer wipe(master.ChainCode)
+
+	e.result = append(e.result, "Fingerprint: "+hex.EncodeToString(bip32.Hash160(master.PublicKeyBytes())[:4]))
+}
+
+// render draws the whole screen.
+func (e *editor) render(w io.Writer) {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format+"\r\n", args...)
+	}
+
+	b.WriteString(clearScreen)
+	size := "12 to 24 words"
+	if e.size != 0 {
+		size = fmt.Sprintf("%d words", e.size)
+	}
+	line("BIP39 mnemonic entry, %s, %s", e.lang, size)
+	line("Tab or Space completes a word, Backspace edits, Enter checks the mnemonic, Ctrl-C quits.")
+	line("")
+
+	for i, index := range e.indexes {
+		fmt.Fprintf(&b, "%3d %-12s", i+1, e.normalized[index])
+		if i%4 == 3 || i == len(e.indexes)-1 {
+			line("")
+		}
+	}
+	line("")
+
+	switch e.state {
+	case stateWords:
+		var matches []int
+		if len(e.current) > 0 {
+			matches, _ = e.matches(e.current)
+		}
+		word := string(e.current)
+		if len(e.current) > 0 && len(matches) == 0 {
+			word = reverseVideo + word + resetVideo
+		}
+		line("Word %d: %s", len(e.indexes)+1, word)
+		if len(e.current) > 0 && len(matches) > 0 {
+			suggestions := make([]string, 0, maxSuggestions)
+			for _, i := range matches {
+				if len(suggestions) == maxSuggestions {
+					suggestions = append(suggestions, "...")
+					break
+				}
+				suggestions = append(suggestions, e.normalized[i])
+			}
+			line("        %s", strings.Join(suggestions, " "))
+		} else if len(e.current) > 0 {
+			line("        No word starts with these letters")
+		} else {
+			line("")
+		}
+		line("")
+		line("%s", e.message)
+	case statePassphrase:
+		line("Checksum valid")
+		line("")
+		line("Passphrase, not shown, empty for none, then Enter:")
+	case stateResult:
+		for _, result := range e.result {
+			line("%s", result)
+		}
+		line("")
+		line("Press any key to clear the screen and exit.")
+	}
+
+	_, _ = io.WriteString(w, b.String())
+}
+
+// wipe zeroes the words and the typed input.
+func (e *editor) wipe() {
+	for i := range e.indexes {
+		e.indexes[i] = 0
+	}
+	e.indexes = e.indexes[:0]
+	wipe(e.current[:cap(e.current)])
+	e.current = e.current[:0]
+	e.mnemonic.Wipe()
+}
+
+// commonPrefix returns the longest prefix of the words at indexes.
+func commonPrefix(words []string, indexes []int) string {
+	prefix := words[indexes[0]]
+	for _, i := range indexes[1:] {
+		n := 0
+		for n < len(prefix) && n < len(words[i]) && prefix[n] == words[i][n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+
+	// Do not cut a character in half.
+	for len(prefix) > 0 && !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+
+	return prefix
+}
+
+func deleteLastRune(b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	_, size := utf8.DecodeLastRune(b)
+	wipe(b[len(b)-size:])
+
+	return b[:len(b)-size]
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
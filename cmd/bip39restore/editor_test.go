@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// runEditor types keys one by one into a new editor and returns it with the
+// last screen drawn.
+func runEditor(t *testing.T, lang string, size int, fingerprint bool, keys string) (*editor, string, int) {
+	e, err := newEditor(lang, size, fingerprint)
+	assert.Nil(t, err)
+
+	var out bytes.Buffer
+	code := edit(e, iotest.OneByteReader(strings.NewReader(keys)), &out)
+	screens := strings.Split(out.String(), clearScreen)
+
+	return e, screens[len(screens)-1], code
+}
+
+// accepted returns the words accepted by e.
+func accepted(e *editor) []string {
+	words := []string{}
+	for _, index := range e.indexes {
+		words = append(words, e.normalized[index])
+	}
+
+	return words
+}
+
+func TestEditorCompletion(t *testing.T) {
+	e, err := newEditor("english", 0, false)
+	assert.Nil(t, err)
+
+	for _, b := range []byte("aba ") {
+		e.key(b)
+	}
+	assert.EqualStringsSlices(t, []string{"abandon"}, accepted(e))
+
+	// Tab completes the prefix shared by the matching words.
+	for _, b := range []byte("abs\t") {
+		e.key(b)
+	}
+	assert.EqualString(t, "abs", string(e.current))
+	for _, b := range []byte("o\t") {
+		e.key(b)
+	}
+	assert.EqualString(t, "", string(e.current))
+	assert.EqualStringsSlices(t, []string{"abandon", "absorb"}, accepted(e))
+
+	// A word that is also the prefix of other words is accepted.
+	for _, b := range []byte("act ") {
+		e.key(b)
+	}
+	assert.EqualStringsSlices(t, []string{"abandon", "absorb", "act"}, accepted(e))
+
+	// Space on an ambiguous prefix keeps it.
+	for _, b := range []byte("ab ") {
+		e.key(b)
+	}
+	assert.EqualString(t, "ab", string(e.current))
+	assert.True(t, strings.Contains(e.message, "words start with these letters"))
+
+	// Backspace deletes letters, then brings back the previous word.
+	for _, b := range []byte{keyDelete, keyDelete, keyBackspace} {
+		e.key(b)
+	}
+	assert.EqualString(t, "act", string(e.current))
+	assert.EqualStringsSlices(t, []string{"abandon", "absorb"}, accepted(e))
+
+	// Arrow keys are ignored.
+	for _, b := range []byte("\x1b[A\x1b[D\x1bOB") {
+		e.key(b)
+	}
+	assert.EqualString(t, "act", string(e.current))
+	assert.Equal(t, stateWords, e.state)
+
+	e.key(keyCtrlU)
+	assert.EqualString(t, "", string(e.current))
+
+	e.wipe()
+	assert.Equal(t, 0, len(e.indexes))
+}
+
+func TestEditorUnknownWord(t *testing.T) {
+	e, screen, code := runEditor(t, "english", 0, false, "abandon xyz \x03")
+	assert.Equal(t, exitError, code)
+	assert.EqualStringsSlices(t, []string{"abandon"}, accepted(e))
+	assert.True(t, strings.Contains(screen, reverseVideo+"xyz"+resetVideo))
+	assert.True(t, strings.Contains(screen, "No word starts with these letters"))
+	assert.True(t, strings.Contains(screen, "Unknown word"))
+}
+
+func TestEditorChecksum(t *testing.T) {
+	words := strings.Repeat("aban ", 11)
+
+	_, screen, code := runEditor(t, "english", 0, false, words+"aban\r\x04")
+	assert.Equal(t, exitError, code)
+	assert.True(t, strings.Contains(screen, "Checksum incorrect"))
+
+	_, screen, code = runEditor(t, "english", 0, false, words+"\r\x04")
+	assert.Equal(t, exitError, code)
+	assert.True(t, strings.Contains(screen, "A mnemonic has 12, 15, 18, 21 or 24 words"))
+
+	// The last word can be brought back and retyped after a failed check.
+	e, screen, code := runEditor(t, "english", 0, false, words+"aban\r\x7f\x15abou\r")
+	assert.Equal(t, exitOK, code)
+	assert.True(t, e.valid)
+	assert.True(t, strings.Contains(screen, "Checksum valid"))
+	assert.True(t, strings.Contains(screen, "Press any key"))
+
+	// The entry is checked once the expected number of words is typed.
+	_, screen, code = runEditor(t, "english", 12, false, words+"abou ")
+	assert.Equal(t, exitOK, code)
+	assert.True(t, strings.Contains(screen, "Checksum valid"))
+}
+
+func TestEditorFingerprint(t *testing.T) {
+	words := strings.Repeat("aban ", 11) + "abou\r"
+
+	_, screen, code := runEditor(t, "english", 0, true, words+"\r")
+	assert.Equal(t, exitOK, code)
+	assert.True(t, strings.Contains(screen, "Fingerprint: 73c5da0a"))
+
+	// The passphrase is never displayed.
+	e, screen, _ := runEditor(t, "english", 0, true, words+"TREZOR")
+	assert.Equal(t, statePassphrase, e.state)
+	assert.False(t, strings.Contains(screen, "TREZOR"))
+}
+
+func TestEditorNormalization(t *testing.T) {
+	entropy := make([]byte, 16)
+	mnemonic, err := bip39.NewMnemonic("japanese", entropy)
+	assert.Nil(t, err)
+
+	// The wordlist is decomposed but typed words are composed.
+	keys := strings.Repeat("あいこくしん ", 11) + "あおぞら\r"
+	e, _, code := runEditor(t, "japanese", 0, false, keys)
+	assert.Equal(t, exitOK, code)
+	assert.EqualString(t, "あおぞら", e.normalized[e.indexes[11]])
+	assert.EqualString(t, mnemonic[strings.LastIndex(mnemonic, " ")+1:], e.list[e.indexes[11]])
+}
+
+func TestNewEditor(t *testing.T) {
+	_, err := newEditor("klingon", 0, false)
+	assert.Equal(t, bip39.ErrInvalidLanguage, err)
+
+	_, err = newEditor("english", 13, false)
+	assert.Equal(t, bip39.ErrMnemonicSizeInvalid, err)
+}
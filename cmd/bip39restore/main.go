@@ -0,0 +1,112 @@
+// Command bip39restore is a terminal interface to type a BIP39 mnemonic on a
+// restore station.
+//
+// Usage:
+//
+//	bip39restore [-lang english] [-words 24] [-fingerprint]
+//
+// The words are typed one by one and completed from the wordlist with Tab or
+// Space, and a word no word of the wordlist starts with is highlighted at
+// once. Enter checks the checksum of the mnemonic, after which -fingerprint
+// asks for a passphrase and shows the master key fingerprint of the wallet.
+//
+// The entry runs in the alternate screen of the terminal, which is cleared
+// along with the scrollback on exit. The input is read in raw mode, so it is
+// never written to a shell or readline history, and the words are wiped from
+// memory on exit. The exit status is 0 for a valid mnemonic, 1 otherwise and
+// 2 for invalid usage.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Exit statuses.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+)
+
+const (
+	alternateScreen = "\x1b[?1049h"
+	normalScreen    = "\x1b[?1049l"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command line args on the terminal stdin and returns the exit
+// status.
+func run(args []string, stdin *os.File, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bip39restore", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	lang := fs.String("lang", "english", "language of the mnemonic")
+	words := fs.Int("words", 0, "number of words, 12, 15, 18, 21 or 24, or 0 for any")
+	fingerprint := fs.Bool("fingerprint", false, "ask for a passphrase and show the master key fingerprint")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "bip39restore: unexpected argument %q\n", fs.Arg(0))
+		return exitUsage
+	}
+
+	e, err := newEditor(*lang, *words, *fingerprint)
+	if err != nil {
+		fmt.Fprintf(stderr, "bip39restore: %v\n", err)
+		return exitUsage
+	}
+	defer e.wipe()
+
+	fd := int(stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Fprintln(stderr, "bip39restore: stdin is not a terminal")
+		return exitUsage
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		fmt.Fprintf(stderr, "bip39restore: %v\n", err)
+		return exitError
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	fmt.Fprint(stdout, alternateScreen)
+	defer fmt.Fprint(stdout, clearScreen+normalScreen)
+
+	return edit(e, stdin, stdout)
+}
+
+// edit runs the editor on the keys read from in until the entry is over, and
+// returns the exit status.
+func edit(e *editor, in io.Reader, out io.Writer) int {
+	var buf [64]byte
+	defer wipe(buf[:])
+	for e.state != stateExit {
+		e.render(out)
+
+		n, err := in.Read(buf[:])
+		for _, b := range buf[:n] {
+			e.key(b)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if !e.valid {
+		return exitError
+	}
+
+	return exitOK
+}
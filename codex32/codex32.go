@@ -0,0 +1,503 @@
+// Package codex32 implements BIP93 codex32 strings: the encoding of a master
+// seed, such as the entropy of a BIP39 mnemonic, in a bech32 alphabet string
+// with a BCH checksum that can be computed and verified by hand, together with
+// Shamir's secret sharing of the seed over GF(32).
+package codex32
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+
+	"github.com/decen-one/go-bip39"
+)
+
+const (
+	charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	prefix  = "ms1"
+
+	// SecretIndex is the share index of the unshared secret.
+	SecretIndex = 's'
+
+	// shareIndexes is the order in which share indexes are assigned.
+	shareIndexes = "acdefghjklmnpqrtuvwxyz023456789"
+
+	headerLength = 6
+
+	// Strings with more than maxShortData characters before the checksum
+	// use the long checksum.
+	maxShortData = 80
+
+	maxShortLength = 93
+	minLongLength  = 96
+	maxLongLength  = 124
+
+	minSecretLength = 16
+	maxSecretLength = 64
+)
+
+var (
+	// ErrInvalidCharacter is returned when a codex32 string contains a
+	// character outside of the bech32 alphabet.
+	ErrInvalidCharacter = errors.New("Invalid codex32 character")
+
+	// ErrMixedCase is returned when a codex32 string mixes upper and lower
+	// case characters.
+	ErrMixedCase = errors.New("Codex32 string must not mix upper and lower case")
+
+	// ErrInvalidPrefix is returned when a codex32 string does not start with
+	// "ms1".
+	ErrInvalidPrefix = errors.New("Codex32 string must start with ms1")
+
+	// ErrInvalidLength is returned when a codex32 string or its payload has
+	// an invalid length.
+	ErrInvalidLength = errors.New("Invalid codex32 string length")
+
+	// ErrChecksumIncorrect is returned when a codex32 string has the
+	// incorrect checksum.
+	ErrChecksumIncorrect = errors.New("Checksum incorrect")
+
+	// ErrInvalidThreshold is returned when the threshold is not 0 or between
+	// 2 and 9, or the share count is lower than the threshold.
+	ErrInvalidThreshold = errors.New("Invalid threshold or share count")
+
+	// ErrInvalidShareIndex is returned when an unshared secret does not have
+	// the share index s.
+	ErrInvalidShareIndex = errors.New("Invalid share index")
+
+	// ErrInvalidIdentifier is returned when an identifier is not 4 bech32
+	// characters.
+	ErrInvalidIdentifier = errors.New("Identifier must be 4 bech32 characters")
+
+	// ErrMismatchedShares is returned when combining shares with different
+	// thresholds, identifiers or lengths.
+	ErrMismatchedShares = errors.New("Shares do not belong to the same secret")
+
+	// ErrDuplicateShares is returned when combining shares with the same
+	// index.
+	ErrDuplicateShares = errors.New("Multiple shares with the same index")
+
+	// ErrInsufficientShares is returned when combining fewer shares than the
+	// threshold.
+	ErrInsufficientShares = errors.New("Insufficient number of shares")
+)
+
+// checksum holds the parameters of the short and long BCH codes.
+type checksum struct {
+	// generator is the generator polynomial without its leading term,
+	// highest degree first.
+	generator []byte
+
+	// target is the residue of a valid string.
+	target []byte
+}
+
+var (
+	shortChecksum = checksum{generator: mustValues("em3gqeeelmcss"), target: mustValues("secretshare32")}
+	longChecksum  = checksum{generator: mustValues("02e6fe4xh4x9kyh"), target: mustValues("secretshare32ex")}
+
+	// initialResidue is the residue before the first character.
+	initialResidue = mustValues("prrqdn")
+)
+
+// Share is a decoded codex32 string.
+type Share struct {
+	// Threshold is the number of shares needed to recover the secret, or 0
+	// for an unshared secret.
+	Threshold int
+
+	// Identifier is the 4 character identifier common to all shares of a
+	// secret.
+	Identifier string
+
+	// Index is the share index character, SecretIndex for the secret.
+	Index byte
+
+	// Payload is the share data, the master seed for the secret.
+	Payload []byte
+}
+
+// Encode encodes a share as a lower case codex32 string.
+func Encode(threshold int, identifier string, index byte, payload []byte) (string, error) {
+	if threshold != 0 && (threshold < 2 || threshold > 9) {
+		return "", ErrInvalidThreshold
+	}
+	if threshold == 0 && lowerIndex(index) != SecretIndex {
+		return "", ErrInvalidShareIndex
+	}
+	identifier = strings.ToLower(identifier)
+	if len(identifier) != 4 || !isValid(identifier) {
+		return "", ErrInvalidIdentifier
+	}
+	index = lowerIndex(index)
+	if strings.IndexByte(charset, index) < 0 {
+		return "", ErrInvalidShareIndex
+	}
+	if len(payload) < minSecretLength || len(payload) > maxSecretLength {
+		return "", ErrInvalidLength
+	}
+
+	header := string(rune('0'+threshold)) + identifier + string(index)
+	data, _ := toValues(header) // err is always nil for the validated header
+	data = append(data, convertBits(payload)...)
+
+	c := shortChecksum
+	if len(data) > maxShortData {
+		c = longChecksum
+	}
+	residue := polymod(c, append(data[:len(data):len(data)], make([]byte, len(c.target))...))
+	for i := range residue {
+		data = append(data, residue[i]^c.target[i])
+	}
+
+	return prefix + fromValues(data), nil
+}
+
+// Decode decodes and verifies a codex32 string. Upper case strings are
+// accepted.
+func Decode(s string) (*Share, error) {
+	data, err := decodeData(s)
+	if err != nil {
+		return nil, err
+	}
+
+	c := checksumOf(len(data))
+	if !equal(polymod(c, data), c.target) {
+		return nil, ErrChecksumIncorrect
+	}
+
+	header := fromValues(data[:headerLength])
+	share := &Share{
+		Identifier: header[1:5],
+		Index:      header[5],
+	}
+
+	switch {
+	case header[0] == '0':
+		if share.Index != SecretIndex {
+			return nil, ErrInvalidShareIndex
+		}
+	case header[0] >= '2' && header[0] <= '9':
+		share.Threshold = int(header[0] - '0')
+	default:
+		return nil, ErrInvalidThreshold
+	}
+
+	// Up to 4 padding bits of any value follow the payload.
+	payload := data[headerLength : len(data)-len(c.target)]
+	if len(payload)*5%8 > 4 {
+		return nil, ErrInvalidLength
+	}
+	share.Payload = make([]byte, len(payload)*5/8)
+	acc, bits, n := 0, 0, 0
+	for _, v := range payload {
+		acc = acc<<5 | int(v)
+		bits += 5
+		if bits >= 8 && n < len(share.Payload) {
+			bits -= 8
+			share.Payload[n] = byte(acc >> bits)
+			n++
+		}
+	}
+	if len(share.Payload) < minSecretLength || len(share.Payload) > maxSecretLength {
+		return nil, ErrInvalidLength
+	}
+
+	return share, nil
+}
+
+// String encodes the share as a codex32 string with zero padding bits.
+func (s *Share) String() string {
+	str, _ := Encode(s.Threshold, s.Identifier, s.Index, s.Payload) // err is always nil for decoded shares
+	return str
+}
+
+// Split splits a master seed into count shares, any threshold of which
+// recover it. The first threshold - 1 shares are random and the others are
+// interpolated from them and the secret, as specified by BIP93.
+func Split(secret []byte, identifier string, threshold int, count int) ([]string, error) {
+	if threshold < 2 || threshold > 9 || count < threshold || count > len(shareIndexes) {
+		return nil, ErrInvalidThreshold
+	}
+
+	secretShare, err := Encode(threshold, identifier, SecretIndex, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]string, 0, count)
+	for i := 0; i < threshold-1; i++ {
+		payload := make([]byte, len(secret))
+		_, _ = rand.Read(payload) // err is always nil
+
+		share, err := Encode(threshold, identifier, shareIndexes[i], payload)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	base := append([]string{secretShare}, shares...)
+	for i := threshold - 1; i < count; i++ {
+		share, err := Interpolate(base, shareIndexes[i])
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// Interpolate derives the share with the given index from threshold shares
+// of a secret. The first threshold shares are used.
+func Interpolate(shares []string, index byte) (string, error) {
+	if len(shares) == 0 {
+		return "", ErrInsufficientShares
+	}
+
+	values := make([][]byte, 0, len(shares))
+	var first *Share
+	for _, s := range shares {
+		share, err := Decode(s)
+		if err != nil {
+			return "", err
+		}
+		if first == nil {
+			first = share
+		}
+		if share.Threshold != first.Threshold || share.Identifier != first.Identifier || len(s) != len(shares[0]) {
+			return "", ErrMismatchedShares
+		}
+
+		data, _ := decodeData(s) // err is always nil for decoded shares
+		values = append(values, data)
+	}
+
+	if first.Threshold == 0 {
+		return "", ErrInvalidThreshold
+	}
+	if len(values) < first.Threshold {
+		return "", ErrInsufficientShares
+	}
+	values = values[:first.Threshold]
+
+	position := strings.IndexByte(charset, lowerIndex(index))
+	if position < 0 {
+		return "", ErrInvalidShareIndex
+	}
+	target := byte(position)
+
+	indexes := make([]byte, len(values))
+	for i, data := range values {
+		indexes[i] = data[headerLength-1]
+		for _, other := range indexes[:i] {
+			if other == indexes[i] {
+				return "", ErrDuplicateShares
+			}
+		}
+	}
+
+	result := make([]byte, len(values[0]))
+	for i, data := range values {
+		if indexes[i] == target {
+			return prefix + fromValues(data), nil
+		}
+
+		// weight is the Lagrange basis polynomial of the share evaluated at
+		// the target index.
+		weight := byte(1)
+		for j, other := range indexes {
+			if j != i {
+				weight = gf32Div(gf32Mul(weight, target^other), indexes[i]^other)
+			}
+		}
+
+		for k, v := range data {
+			result[k] ^= gf32Mul(weight, v)
+		}
+	}
+
+	return prefix + fromValues(result), nil
+}
+
+// Combine recovers the master seed from an unshared secret or from threshold
+// shares.
+func Combine(shares []string) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrInsufficientShares
+	}
+
+	first, err := Decode(shares[0])
+	if err != nil {
+		return nil, err
+	}
+	if first.Threshold == 0 {
+		return first.Payload, nil
+	}
+
+	secret, err := Interpolate(shares, SecretIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := Decode(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return share.Payload, nil
+}
+
+// FromMnemonic encodes the entropy of a mnemonic as an unshared codex32
+// secret.
+func FromMnemonic(lang string, mnemonic string, identifier string) (string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(0, identifier, SecretIndex, entropy)
+}
+
+// SplitMnemonic splits the entropy of a mnemonic into count codex32 shares,
+// any threshold of which recover it.
+func SplitMnemonic(lang string, mnemonic string, identifier string, threshold int, count int) ([]string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	return Split(entropy, identifier, threshold, count)
+}
+
+// ToMnemonic recovers the mnemonic of the given language from an unshared
+// codex32 secret or from threshold shares.
+func ToMnemonic(lang string, shares []string) (string, error) {
+	entropy, err := Combine(shares)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(lang, entropy)
+}
+
+// decodeData checks the case, prefix, characters and length of a codex32
+// string and returns the values of its data part.
+func decodeData(s string) ([]byte, error) {
+	lower := strings.ToLower(s)
+	if s != lower && s != strings.ToUpper(s) {
+		return nil, ErrMixedCase
+	}
+	if !strings.HasPrefix(lower, prefix) {
+		return nil, ErrInvalidPrefix
+	}
+
+	data, err := toValues(lower[len(prefix):])
+	if err != nil {
+		return nil, err
+	}
+	if checksumOf(len(data)).target == nil {
+		return nil, ErrInvalidLength
+	}
+
+	return data, nil
+}
+
+// checksumOf returns the checksum of a data part of the given length, or an
+// empty checksum for an invalid length.
+func checksumOf(length int) checksum {
+	switch {
+	case length >= headerLength+len(shortChecksum.target) && length <= maxShortLength:
+		return shortChecksum
+	case length >= minLongLength && length <= maxLongLength:
+		return longChecksum
+	default:
+		return checksum{}
+	}
+}
+
+// polymod returns the residue of the values modulo the generator polynomial.
+func polymod(c checksum, values []byte) []byte {
+	residue := make([]byte, len(c.generator))
+	copy(residue[len(residue)-len(initialResidue):], initialResidue)
+
+	for _, v := range values {
+		top := residue[0]
+		copy(residue, residue[1:])
+		residue[len(residue)-1] = v
+
+		for i, g := range c.generator {
+			residue[i] ^= gf32Mul(top, g)
+		}
+	}
+
+	return residue
+}
+
+// convertBits converts bytes into 5 bit values, padding with zero bits.
+func convertBits(data []byte) []byte {
+	values := make([]byte, 0, (len(data)*8+4)/5)
+	acc, bits := 0, 0
+	for _, b := range data {
+		acc = acc<<8 | int(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			values = append(values, byte(acc>>bits&31))
+		}
+	}
+	if bits > 0 {
+		values = append(values, byte(acc<<(5-bits)&31))
+	}
+
+	return values
+}
+
+func toValues(s string) ([]byte, error) {
+	values := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(charset, s[i])
+		if v < 0 {
+			return nil, ErrInvalidCharacter
+		}
+		values[i] = byte(v)
+	}
+
+	return values, nil
+}
+
+func mustValues(s string) []byte {
+	values, err := toValues(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return values
+}
+
+func fromValues(values []byte) string {
+	var sb strings.Builder
+	for _, v := range values {
+		sb.WriteByte(charset[v])
+	}
+
+	return sb.String()
+}
+
+func lowerIndex(index byte) byte {
+	if index >= 'A' && index <= 'Z' {
+		return index + 'a' - 'A'
+	}
+
+	return index
+}
+
+func isValid(s string) bool {
+	_, err := toValues(s)
+	return err == nil
+}
+
+func equal(a []byte, b []byte) bool {
+	return string(a) == string(b)
+}
@@ -0,0 +1,260 @@
+package codex32
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// Test vectors from https://github.com/bitcoin/bips/blob/master/bip-0093.mediawiki
+var testVectors = []struct {
+	shares []string
+	secret string
+	seed   string
+}{
+	{
+		shares: []string{"ms10testsxxxxxxxxxxxxxxxxxxxxxxxxxx4nzvca9cmczlw"},
+		secret: "ms10testsxxxxxxxxxxxxxxxxxxxxxxxxxx4nzvca9cmczlw",
+		seed:   "318c6318c6318c6318c6318c6318c631",
+	},
+	{
+		shares: []string{
+			"MS12NAMEA320ZYXWVUTSRQPNMLKJHGFEDCAXRPP870HKKQRM",
+			"MS12NAMECACDEFGHJKLMNPQRSTUVWXYZ023FTR2GDZMPY6PN",
+		},
+		secret: "ms12names6xqguzttxkeqnjsjzv4jv3nz5k3kwgsphuh6evw",
+		seed:   "d1808e096b35b209ca12132b264662a5",
+	},
+	{
+		shares: []string{
+			"ms13casha320zyxwvutsrqpnmlkjhgfedca2a8d0zehn8a0t",
+			"ms13cashcacdefghjklmnpqrstuvwxyz023949xq35my48dr",
+			"ms13cashd0wsedstcdcts64cd7wvy4m90lm28w4ffupqs7rm",
+		},
+		secret: "ms13cashsllhdmn9m42vcsamx24zrxgs3qqjzqud4m0d6nln",
+		seed:   "ffeeddccbbaa99887766554433221100",
+	},
+	{
+		shares: []string{"ms10leetsllhdmn9m42vcsamx24zrxgs3qrl7ahwvhw4fnzrhve25gvezzyqqtum9pgv99ycma"},
+		secret: "ms10leetsllhdmn9m42vcsamx24zrxgs3qrl7ahwvhw4fnzrhve25gvezzyqqtum9pgv99ycma",
+		seed:   "ffeeddccbbaa99887766554433221100ffeeddccbbaa99887766554433221100",
+	},
+	{
+		shares: []string{"MS100C8VSM32ZXFGUHPCHTLUPZRY9X8GF2TVDW0S3JN54KHCE6MUA7LQPZYGSFJD6AN074RXVCEMLH8WU3TK925ACDEFGHJKLMNPQRSTUVWXY06FHPV80UNDVARHRAK"},
+		secret: "ms100c8vsm32zxfguhpchtlupzry9x8gf2tvdw0s3jn54khce6mua7lqpzygsfjd6an074rxvcemlh8wu3tk925acdefghjklmnpqrstuvwxy06fhpv80undvarhrak",
+		seed:   "dc5423251cb87175ff8110c8531d0952d8d73e1194e95b5f19d6f9df7c01111104c9baecdfea8cccc677fb9ddc8aec5553b86e528bcadfdcc201c17c638c47e9",
+	},
+}
+
+func TestCombine(t *testing.T) {
+	for _, vector := range testVectors {
+		seed, err := Combine(vector.shares)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.seed, hex.EncodeToString(seed))
+
+		if len(vector.shares) > 1 {
+			secret, err := Interpolate(vector.shares, SecretIndex)
+			assert.Nil(t, err)
+			assert.EqualString(t, vector.secret, secret)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	// The derived shares of the BIP93 vectors.
+	for _, vector := range []struct {
+		shares []string
+		index  byte
+		share  string
+	}{
+		{testVectors[1].shares, 'd', "ms12namedll4f8jlh4e5vdvuldlfxu2jhdnlsm97xvenrxeg"},
+		{testVectors[2].shares, 'e', "ms13casheekgpemxzshcrmqhaydlp6yhms3ws7320xyxsar9"},
+		{testVectors[2].shares, 'f', "ms13cashf8jh6sdrkpyrsp5ut94pj8ktehhw2hfvyrj48704"},
+	} {
+		share, err := Interpolate(vector.shares, vector.index)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.share, share)
+	}
+
+	shares := testVectors[2].shares
+	_, err := Interpolate(shares[:2], 'e')
+	assert.Equal(t, ErrInsufficientShares, err)
+
+	_, err = Interpolate([]string{shares[0], shares[0], shares[1]}, 'e')
+	assert.Equal(t, ErrDuplicateShares, err)
+
+	_, err = Interpolate([]string{shares[0], testVectors[1].shares[1], shares[1]}, 'e')
+	assert.Equal(t, ErrMismatchedShares, err)
+
+	_, err = Interpolate(testVectors[0].shares, 'a')
+	assert.Equal(t, ErrInvalidThreshold, err)
+
+	_, err = Interpolate(shares, 'b')
+	assert.Equal(t, ErrInvalidShareIndex, err)
+}
+
+func TestDecode(t *testing.T) {
+	share, err := Decode(testVectors[1].shares[0])
+	assert.Nil(t, err)
+	assert.Equal(t, 2, share.Threshold)
+	assert.EqualString(t, "name", share.Identifier)
+	assert.Equal(t, byte('a'), share.Index)
+	assert.EqualString(t, "8a9e2219cce2e030067bfda574272dc7", hex.EncodeToString(share.Payload))
+
+	// Re-encoding clears the padding bits but keeps the share.
+	reencoded, err := Decode(share.String())
+	assert.Nil(t, err)
+	assert.EqualString(t, share.Identifier, reencoded.Identifier)
+	assert.Equal(t, share.Index, reencoded.Index)
+	assert.EqualByteSlices(t, share.Payload, reencoded.Payload)
+
+	// The padding bits of the first vector are not zero.
+	share, err = Decode(testVectors[0].secret)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, share.Threshold)
+	assert.Equal(t, byte(SecretIndex), share.Index)
+
+	secret := testVectors[0].secret
+	for _, invalid := range []struct {
+		s   string
+		err error
+	}{
+		{"ms10testsxxxxxxxxxxxxxxxxxxxxxxxxxx4nzvca9cmczlx", ErrChecksumIncorrect},
+		{"ms10testsxxxxxxxxxxxxxxxxxxxxxxxxxx4nzvca9cmczlW", ErrMixedCase},
+		{"mc10testsxxxxxxxxxxxxxxxxxxxxxxxxxx4nzvca9cmczlw", ErrInvalidPrefix},
+		{"ms10testsxxxxxxxxxxxxxxxxxxxxxxxxxx4nzvca9cmczlb", ErrInvalidCharacter},
+		{secret[:20], ErrInvalidLength},
+		{secret + strings.Repeat("q", 50), ErrInvalidLength},
+	} {
+		_, err := Decode(invalid.s)
+		assert.Equal(t, invalid.err, err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, vector := range testVectors {
+		share, err := Decode(vector.secret)
+		assert.Nil(t, err)
+
+		s, err := Encode(share.Threshold, strings.ToUpper(share.Identifier), share.Index, share.Payload)
+		assert.Nil(t, err)
+		assert.Equal(t, len(vector.secret), len(s))
+
+		// Only the padding bits and the checksum may differ.
+		checksumLength := len(shortChecksum.target)
+		if len(s) > len(prefix)+maxShortLength {
+			checksumLength = len(longChecksum.target)
+		}
+		payloadEnd := len(s) - checksumLength - 1
+		assert.EqualString(t, vector.secret[:payloadEnd], s[:payloadEnd])
+
+		decoded, err := Decode(s)
+		assert.Nil(t, err)
+		assert.EqualByteSlices(t, share.Payload, decoded.Payload)
+	}
+
+	payload := make([]byte, 16)
+	for _, invalid := range []struct {
+		threshold  int
+		identifier string
+		index      byte
+		payload    []byte
+		err        error
+	}{
+		{1, "test", 'a', payload, ErrInvalidThreshold},
+		{10, "test", 'a', payload, ErrInvalidThreshold},
+		{0, "test", 'a', payload, ErrInvalidShareIndex},
+		{2, "test", 'b', payload, ErrInvalidShareIndex},
+		{2, "tes", 'a', payload, ErrInvalidIdentifier},
+		{2, "tesb", 'a', payload, ErrInvalidIdentifier},
+		{2, "test", 'a', payload[:15], ErrInvalidLength},
+		{2, "test", 'a', make([]byte, 65), ErrInvalidLength},
+	} {
+		_, err := Encode(invalid.threshold, invalid.identifier, invalid.index, invalid.payload)
+		assert.Equal(t, invalid.err, err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for _, size := range []int{16, 32, 64} {
+		secret := make([]byte, size)
+		_, _ = rand.Read(secret)
+
+		shares, err := Split(secret, "cash", 3, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, len(shares))
+
+		for _, subset := range [][]string{shares[:3], {shares[4], shares[1], shares[3]}} {
+			recovered, err := Combine(subset)
+			assert.Nil(t, err)
+			assert.EqualByteSlices(t, secret, recovered)
+		}
+	}
+
+	_, err := Split(make([]byte, 16), "cash", 1, 3)
+	assert.Equal(t, ErrInvalidThreshold, err)
+
+	_, err = Split(make([]byte, 16), "cash", 3, 2)
+	assert.Equal(t, ErrInvalidThreshold, err)
+}
+
+func TestCorrect(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for _, vector := range testVectors {
+		s := strings.ToLower(vector.shares[0])
+
+		corrected, positions, err := Correct(s)
+		assert.Nil(t, err)
+		assert.EqualString(t, s, corrected)
+		assert.Equal(t, 0, len(positions))
+
+		for errors := 1; errors <= 4; errors++ {
+			for trial := 0; trial < 20; trial++ {
+				corrupted := []byte(s)
+				for _, position := range random.Perm(len(s) - len(prefix))[:errors] {
+					position += len(prefix)
+					original := strings.IndexByte(charset, corrupted[position])
+					corrupted[position] = charset[(original+1+random.Intn(31))%32]
+				}
+
+				corrected, positions, err := Correct(string(corrupted))
+				assert.Nil(t, err)
+				assert.EqualString(t, s, corrected)
+				assert.Equal(t, errors, len(positions))
+			}
+		}
+	}
+
+	_, _, err := Correct("ms10tesTsxxxxxxxxxxxxxxxxxxxxxxxxxx4nzvca9cmczlw")
+	assert.Equal(t, ErrMixedCase, err)
+
+	// 5 errors are detected but can not be corrected.
+	_, _, err = Correct("ms10qqqqsxxxqxxxxxxxxxxxxxxxxxxxxxx4nzvca9cmczlw")
+	assert.Equal(t, ErrUncorrectable, err)
+}
+
+func TestMnemonic(t *testing.T) {
+	mnemonic := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+
+	secret, err := FromMnemonic("english", mnemonic, "leet")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(secret, "ms10leets"))
+
+	recovered, err := ToMnemonic("english", []string{secret})
+	assert.Nil(t, err)
+	assert.EqualString(t, mnemonic, recovered)
+
+	shares, err := SplitMnemonic("english", mnemonic, "leet", 2, 3)
+	assert.Nil(t, err)
+
+	recovered, err = ToMnemonic("english", shares[1:])
+	assert.Nil(t, err)
+	assert.EqualString(t, mnemonic, recovered)
+
+	_, err = ToMnemonic("english", testVectors[2].shares[:1])
+	assert.Equal(t, ErrInsufficientShares, err)
+}
@@ -0,0 +1,257 @@
+package codex32
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// maxCorrections is the number of substitution errors the BCH codes are
+	// guaranteed to correct.
+	maxCorrections = 4
+
+	// syndromeCount is the number of consecutive roots of the generators.
+	syndromeCount = 2 * maxCorrections
+)
+
+// ErrUncorrectable is returned when a codex32 string has more errors than can
+// be corrected.
+var ErrUncorrectable = errors.New("Codex32 string has too many errors to correct")
+
+// gf32Exp and gf32Log hold the powers and logarithms of the generator 2 in
+// GF(32) with the polynomial x^5 + x^3 + 1 used by bech32.
+var gf32Exp, gf32Log [32]byte
+
+// gf1024Exp and gf1024Log hold the powers and logarithms of the primitive
+// element 1 + 3z of GF(1024), the quadratic extension GF(32)[z]/(z^2 + z + 1).
+// Elements are stored as a + b*z = a | b<<5.
+var (
+	gf1024Exp [1023]uint16
+	gf1024Log [1024]int
+)
+
+// bchCode describes the roots of a generator polynomial: step*(first+i) for i
+// in [0, 8) are the logarithms of 8 consecutive roots in GF(1024).
+type bchCode struct {
+	step  int
+	first int
+}
+
+var (
+	// The short generator has roots beta^9 to beta^16 where beta has order 93.
+	shortCode = bchCode{step: 11, first: 9}
+
+	// The long generator has roots beta^-3 to beta^4 where beta has order
+	// 1023.
+	longCode = bchCode{step: 2, first: -3}
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 31; i++ {
+		gf32Exp[i] = x
+		gf32Log[x] = byte(i)
+
+		x <<= 1
+		if x&32 != 0 {
+			x ^= 0x29
+		}
+	}
+
+	y := uint16(1)
+	for i := 0; i < 1023; i++ {
+		gf1024Exp[i] = y
+		gf1024Log[y] = i
+		y = gf1024Mul(y, 1|3<<5)
+	}
+}
+
+func gf32Mul(a byte, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return gf32Exp[(int(gf32Log[a])+int(gf32Log[b]))%31]
+}
+
+func gf32Div(a byte, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+
+	return gf32Exp[(int(gf32Log[a])-int(gf32Log[b])+31)%31]
+}
+
+// gf1024Mul multiplies (a + bz)(c + dz) = ac + bd + (ad + bc + bd)z using
+// z^2 = z + 1. It only relies on the GF(32) tables.
+func gf1024Mul(x uint16, y uint16) uint16 {
+	a, b := byte(x&31), byte(x>>5)
+	c, d := byte(y&31), byte(y>>5)
+	bd := gf32Mul(b, d)
+
+	return uint16(gf32Mul(a, c)^bd) | uint16(gf32Mul(a, d)^gf32Mul(b, c)^bd)<<5
+}
+
+func gf1024Pow(exponent int) uint16 {
+	return gf1024Exp[(exponent%1023+1023)%1023]
+}
+
+func gf1024Div(x uint16, y uint16) uint16 {
+	if x == 0 {
+		return 0
+	}
+
+	return gf1024Pow(gf1024Log[x] - gf1024Log[y])
+}
+
+// evaluate evaluates a polynomial with coefficients lowest degree first.
+func evaluate(coefficients []uint16, x uint16) uint16 {
+	result := uint16(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		if result != 0 {
+			result = gf1024Mul(result, x)
+		}
+		result ^= coefficients[i]
+	}
+
+	return result
+}
+
+// Correct corrects up to 4 substituted characters of a codex32 string with
+// the BCH code of its checksum. It returns the lower case corrected string and
+// the positions of the corrected characters.
+func Correct(s string) (string, []int, error) {
+	data, err := decodeData(s)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c, code := shortChecksum, shortCode
+	if len(data) >= minLongLength {
+		c, code = longChecksum, longCode
+	}
+
+	residue := polymod(c, data)
+	if equal(residue, c.target) {
+		return strings.ToLower(s), nil, nil
+	}
+
+	// The difference to the valid residue is the error polynomial modulo the
+	// generator, so it has the same value at the roots of the generator.
+	difference := make([]uint16, len(residue))
+	for i := range residue {
+		difference[len(residue)-1-i] = uint16(residue[i] ^ c.target[i])
+	}
+
+	syndromes := make([]uint16, syndromeCount)
+	for i := range syndromes {
+		syndromes[i] = evaluate(difference, gf1024Pow(code.step*(code.first+i)))
+	}
+
+	locator := berlekampMassey(syndromes)
+	errorCount := len(locator) - 1
+	if locator == nil || errorCount > maxCorrections {
+		return "", nil, ErrUncorrectable
+	}
+
+	// evaluator is syndromes(x) * locator(x) mod x^8.
+	evaluator := make([]uint16, syndromeCount)
+	for i, syndrome := range syndromes {
+		for j, coefficient := range locator {
+			if i+j < syndromeCount && syndrome != 0 && coefficient != 0 {
+				evaluator[i+j] ^= gf1024Mul(syndrome, coefficient)
+			}
+		}
+	}
+
+	// derivative is the formal derivative of the locator, whose even terms
+	// vanish in characteristic 2.
+	derivative := make([]uint16, len(locator)-1)
+	for i := 1; i < len(locator); i += 2 {
+		derivative[i-1] = locator[i]
+	}
+
+	var positions []int
+	for i := range data {
+		exponent := code.step * (len(data) - 1 - i)
+		inverse := gf1024Pow(-exponent)
+		if evaluate(locator, inverse) != 0 {
+			continue
+		}
+
+		// Forney's algorithm gives the error value, which must lie in
+		// GF(32).
+		denominator := evaluate(derivative, inverse)
+		if denominator == 0 {
+			return "", nil, ErrUncorrectable
+		}
+		value := gf1024Div(evaluate(evaluator, inverse), denominator)
+		if value != 0 {
+			value = gf1024Mul(value, gf1024Pow(exponent*(1-code.first)))
+		}
+		if value == 0 || value >= 32 {
+			return "", nil, ErrUncorrectable
+		}
+
+		data[i] ^= byte(value)
+		positions = append(positions, len(prefix)+i)
+	}
+
+	if len(positions) != errorCount || !equal(polymod(c, data), c.target) {
+		return "", nil, ErrUncorrectable
+	}
+
+	return prefix + fromValues(data), positions, nil
+}
+
+// berlekampMassey returns the error locator polynomial, lowest degree first,
+// of the syndromes, or nil if they do not match any error pattern.
+func berlekampMassey(syndromes []uint16) []uint16 {
+	locator := []uint16{1}
+	previous := []uint16{1}
+	length, shift, previousDiscrepancy := 0, 1, uint16(1)
+
+	for i := range syndromes {
+		discrepancy := syndromes[i]
+		for j := 1; j <= length && j < len(locator); j++ {
+			if locator[j] != 0 && syndromes[i-j] != 0 {
+				discrepancy ^= gf1024Mul(locator[j], syndromes[i-j])
+			}
+		}
+
+		if discrepancy == 0 {
+			shift++
+			continue
+		}
+
+		old := append([]uint16{}, locator...)
+		factor := gf1024Div(discrepancy, previousDiscrepancy)
+		for len(locator) < len(previous)+shift {
+			locator = append(locator, 0)
+		}
+		for j, coefficient := range previous {
+			if coefficient != 0 {
+				locator[j+shift] ^= gf1024Mul(factor, coefficient)
+			}
+		}
+
+		if 2*length <= i {
+			length = i + 1 - length
+			previous = old
+			previousDiscrepancy = discrepancy
+			shift = 1
+		} else {
+			shift++
+		}
+	}
+
+	for len(locator) > 1 && locator[len(locator)-1] == 0 {
+		locator = locator[:len(locator)-1]
+	}
+	if len(locator)-1 != length {
+		// The syndromes do not match any error pattern.
+		return nil
+	}
+
+	return locator
+}
@@ -0,0 +1,205 @@
+// Package descriptor creates BIP380 output script descriptors for the
+// accounts of a mnemonic, ready to be imported into Bitcoin Core.
+package descriptor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decen-one/go-bip39/address"
+)
+
+const (
+	inputCharset    = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	checksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+var (
+	// ErrInvalidCharacter is returned when a descriptor contains a character
+	// outside of the descriptor character set.
+	ErrInvalidCharacter = errors.New("Invalid descriptor character")
+
+	// ErrChecksumIncorrect is returned when a descriptor has the incorrect
+	// checksum.
+	ErrChecksumIncorrect = errors.New("Checksum incorrect")
+
+	// ErrMissingChecksum is returned when verifying a descriptor without a
+	// checksum.
+	ErrMissingChecksum = errors.New("Missing descriptor checksum")
+)
+
+// Descriptor is the account level descriptor of one address type.
+type Descriptor struct {
+	Type address.Type
+
+	// Fingerprint is the master key fingerprint of the key origin.
+	Fingerprint uint32
+
+	// Path is the account derivation path, such as "84h/0h/0h".
+	Path string
+
+	// XPub is the serialized account extended public key.
+	XPub string
+}
+
+// New creates the descriptor of the given address type and account of a
+// mnemonic and passphrase.
+func New(lang string, mnemonic string, passphrase string, t address.Type, net *address.Network, account uint32) (*Descriptor, error) {
+	wallet, err := address.NewWallet(lang, mnemonic, passphrase, net)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromWallet(wallet, t, account)
+}
+
+// FromWallet creates the descriptor of the given address type and account of
+// a wallet.
+func FromWallet(wallet *address.Wallet, t address.Type, account uint32) (*Descriptor, error) {
+	if t.Purpose() == 0 {
+		return nil, address.ErrInvalidType
+	}
+
+	path := address.AccountPath(t, wallet.Network(), account)
+	key, err := wallet.RootKey().Derive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Descriptor{
+		Type:        t,
+		Fingerprint: wallet.RootKey().Fingerprint(),
+		Path:        strings.ReplaceAll(strings.TrimPrefix(path, "m/"), "'", "h"),
+		XPub:        key.PublicKey().String(),
+	}, nil
+}
+
+// String returns the descriptor with the BIP389 multipath <0;1>/* covering
+// both the receive and change chains, and its checksum.
+func (d *Descriptor) String() string {
+	return d.withChildren("<0;1>/*")
+}
+
+// Receive returns the descriptor of the receive chain with its checksum.
+func (d *Descriptor) Receive() string {
+	return d.withChildren("0/*")
+}
+
+// Change returns the descriptor of the change chain with its checksum.
+func (d *Descriptor) Change() string {
+	return d.withChildren("1/*")
+}
+
+func (d *Descriptor) withChildren(children string) string {
+	key := fmt.Sprintf("[%08x/%s]%s/%s", d.Fingerprint, d.Path, d.XPub, children)
+
+	var desc string
+	switch d.Type {
+	case address.P2PKH:
+		desc = "pkh(" + key + ")"
+	case address.P2SHP2WPKH:
+		desc = "sh(wpkh(" + key + "))"
+	case address.P2WPKH:
+		desc = "wpkh(" + key + ")"
+	case address.P2TR:
+		desc = "tr(" + key + ")"
+	}
+
+	desc, _ = AddChecksum(desc) // err is always nil for generated descriptors
+	return desc
+}
+
+// Checksum computes the 8 character checksum of a descriptor without
+// checksum.
+func Checksum(desc string) (string, error) {
+	symbols, err := expand(desc)
+	if err != nil {
+		return "", err
+	}
+
+	checksum := polymod(append(symbols, 0, 0, 0, 0, 0, 0, 0, 0)) ^ 1
+
+	result := make([]byte, 8)
+	for i := range result {
+		result[i] = checksumCharset[(checksum>>(5*(7-i)))&31]
+	}
+
+	return string(result), nil
+}
+
+// AddChecksum appends "#" and the checksum to a descriptor.
+func AddChecksum(desc string) (string, error) {
+	checksum, err := Checksum(desc)
+	if err != nil {
+		return "", err
+	}
+
+	return desc + "#" + checksum, nil
+}
+
+// VerifyChecksum verifies the checksum of a descriptor in the form
+// "desc#checksum" and returns the descriptor without it.
+func VerifyChecksum(descWithChecksum string) (string, error) {
+	pos := strings.LastIndexByte(descWithChecksum, '#')
+	if pos < 0 {
+		return "", ErrMissingChecksum
+	}
+
+	desc := descWithChecksum[:pos]
+	checksum, err := Checksum(desc)
+	if err != nil {
+		return "", err
+	}
+	if checksum != descWithChecksum[pos+1:] {
+		return "", ErrChecksumIncorrect
+	}
+
+	return desc, nil
+}
+
+// expand converts a descriptor into the symbols covered by the checksum.
+func expand(desc string) ([]uint64, error) {
+	symbols := make([]uint64, 0, len(desc)*4/3+1)
+	groups := make([]uint64, 0, 3)
+
+	for _, c := range desc {
+		v := strings.IndexRune(inputCharset, c)
+		if v < 0 {
+			return nil, ErrInvalidCharacter
+		}
+
+		symbols = append(symbols, uint64(v&31))
+		groups = append(groups, uint64(v>>5))
+		if len(groups) == 3 {
+			symbols = append(symbols, groups[0]*9+groups[1]*3+groups[2])
+			groups = groups[:0]
+		}
+	}
+
+	switch len(groups) {
+	case 1:
+		symbols = append(symbols, groups[0])
+	case 2:
+		symbols = append(symbols, groups[0]*3+groups[1])
+	}
+
+	return symbols, nil
+}
+
+func polymod(symbols []uint64) uint64 {
+	generator := [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+
+	chk := uint64(1)
+	for _, value := range symbols {
+		top := chk >> 35
+		chk = (chk&0x7ffffffff)<<5 ^ value
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
@@ -0,0 +1,101 @@
+package descriptor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39/address"
+	"github.com/decen-one/go-bip39/assert"
+	"github.com/decen-one/go-bip39/base58"
+	"github.com/decen-one/go-bip39/bip32"
+)
+
+const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// Test vectors from https://github.com/bitcoin/bips/blob/master/bip-0380.mediawiki
+func TestVerifyChecksum(t *testing.T) {
+	for _, desc := range []string{
+		"raw(deadbeef)#89f8spxm",
+		"sh(multi(2,[00000000/111'/222]xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc,xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L/0))#ggrsrxfy",
+		"sh(multi(2,[00000000/111'/222]xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL,xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y/0))#tjg09x5t",
+	} {
+		_, err := VerifyChecksum(desc)
+		assert.Nil(t, err)
+	}
+
+	for _, desc := range []string{
+		"raw(deadbeef)#",
+		"raw(deadbeef)#89f8spxmx",
+		"raw(deadbeef)#89f8spxn",
+		"raw(deedbeef)#89f8spxm",
+	} {
+		_, err := VerifyChecksum(desc)
+		assert.Equal(t, ErrChecksumIncorrect, err)
+	}
+
+	_, err := VerifyChecksum("raw(deadbeef)")
+	assert.Equal(t, ErrMissingChecksum, err)
+
+	_, err = Checksum("raw(deadébeef)")
+	assert.Equal(t, ErrInvalidCharacter, err)
+}
+
+// toXPub converts a SLIP-132 ypub or zpub into the equivalent xpub.
+func toXPub(t *testing.T, s string) string {
+	data, err := base58.CheckDecode(s)
+	assert.Nil(t, err)
+
+	data[0], data[1], data[2], data[3] = 0x04, 0x88, 0xb2, 0x1e
+	key, err := bip32.Deserialize(data)
+	assert.Nil(t, err)
+
+	return key.String()
+}
+
+func TestNew(t *testing.T) {
+	// Account extended public keys published in BIP49, BIP84 and BIP86.
+	for _, vector := range []struct {
+		addressType address.Type
+		path        string
+		xpub        string
+		script      string
+	}{
+		{address.P2SHP2WPKH, "49h/0h/0h", toXPub(t, "ypub6Ww3ibxVfGzLrAH1PNcjyAWenMTbbAosGNB6VvmSEgytSER9azLDWCxoJwW7Ke7icmizBMXrzBx9979FfaHxHcrArf3zbeJJJUZPf663zsP"), "sh(wpkh("},
+		{address.P2WPKH, "84h/0h/0h", toXPub(t, "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"), "wpkh("},
+		{address.P2TR, "86h/0h/0h", "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ", "tr("},
+	} {
+		d, err := New("english", mnemonic, "", vector.addressType, address.Mainnet, 0)
+		assert.Nil(t, err)
+
+		assert.Equal(t, uint32(0x73c5da0a), d.Fingerprint)
+		assert.EqualString(t, vector.path, d.Path)
+		assert.EqualString(t, vector.xpub, d.XPub)
+
+		key := "[73c5da0a/" + vector.path + "]" + vector.xpub
+		closing := strings.Repeat(")", strings.Count(vector.script, "("))
+		for _, desc := range []struct {
+			withChecksum string
+			children     string
+		}{
+			{d.String(), "/<0;1>/*"},
+			{d.Receive(), "/0/*"},
+			{d.Change(), "/1/*"},
+		} {
+			withoutChecksum, err := VerifyChecksum(desc.withChecksum)
+			assert.Nil(t, err)
+			assert.EqualString(t, vector.script+key+desc.children+closing, withoutChecksum)
+		}
+	}
+}
+
+func TestNewTestnet(t *testing.T) {
+	d, err := New("english", mnemonic, "", address.P2PKH, address.Testnet, 1)
+	assert.Nil(t, err)
+
+	assert.EqualString(t, "44h/1h/1h", d.Path)
+	assert.True(t, strings.HasPrefix(d.XPub, "tpub"))
+	assert.True(t, strings.HasPrefix(d.String(), "pkh([73c5da0a/44h/1h/1h]tpub"))
+
+	_, err = New("english", mnemonic, "", address.Type(42), address.Mainnet, 0)
+	assert.Equal(t, address.ErrInvalidType, err)
+}
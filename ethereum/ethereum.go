@@ -0,0 +1,134 @@
+// Package ethereum derives Ethereum accounts from BIP39 seeds at the BIP44
+// path m/44'/60'/0'/0/index and encodes their EIP-55 checksummed addresses.
+package ethereum
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decen-one/go-bip39/bip32"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	// ErrInvalidAddress is returned when trying to use a malformed address.
+	ErrInvalidAddress = errors.New("Invalid address")
+
+	// ErrInvalidPublicKey is returned when a public key is not a 65 byte
+	// uncompressed key.
+	ErrInvalidPublicKey = errors.New("Invalid uncompressed public key")
+)
+
+// Account is an Ethereum account derived from a seed.
+type Account struct {
+	Path string
+
+	// PrivateKey is the 32 byte secp256k1 private key.
+	PrivateKey []byte
+
+	// PublicKey is the 65 byte uncompressed public key 0x04 || X || Y.
+	PublicKey []byte
+
+	// Address is the EIP-55 checksummed address.
+	Address string
+}
+
+// Path returns the BIP44 derivation path of the account at the given index.
+func Path(index uint32) string {
+	return fmt.Sprintf("m/44'/60'/0'/0/%d", index)
+}
+
+// NewAccount derives the account at the given index from a seed such as the
+// output of bip39.NewSeed.
+func NewAccount(seed []byte, index uint32) (*Account, error) {
+	return DeriveAccount(seed, Path(index))
+}
+
+// DeriveAccount derives the account at the given path from a seed such as
+// the output of bip39.NewSeed.
+func DeriveAccount(seed []byte, path string) (*Account, error) {
+	master, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := master.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := secp256k1.PrivKeyFromBytes(key.Key).PubKey().SerializeUncompressed()
+	address, err := PublicKeyToAddress(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Account{
+		Path:       path,
+		PrivateKey: key.Key,
+		PublicKey:  publicKey,
+		Address:    address,
+	}, nil
+}
+
+// PublicKeyToAddress returns the checksummed address of a 65 byte
+// uncompressed public key 0x04 || X || Y.
+func PublicKeyToAddress(publicKey []byte) (string, error) {
+	if len(publicKey) != 65 || publicKey[0] != 0x04 {
+		return "", ErrInvalidPublicKey
+	}
+
+	return ChecksumAddress(keccak256(publicKey[1:])[12:]), nil
+}
+
+// ChecksumAddress encodes a 20 byte address with the EIP-55 mixed case
+// checksum.
+func ChecksumAddress(address []byte) string {
+	lower := hex.EncodeToString(address)
+	hash := keccak256([]byte(lower))
+
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		// Uppercase letters whose corresponding hash nibble is 8 or more.
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(checksummed)
+}
+
+// ParseAddress decodes a 0x prefixed hex address. Mixed case addresses must
+// have a valid EIP-55 checksum, all lower or upper case addresses are
+// accepted as is.
+func ParseAddress(s string) ([]byte, error) {
+	if len(s) != 42 || (s[:2] != "0x" && s[:2] != "0X") {
+		return nil, ErrInvalidAddress
+	}
+
+	address, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, ErrInvalidAddress
+	}
+
+	digits := s[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && ChecksumAddress(address) != "0x"+digits {
+		return nil, ErrInvalidAddress
+	}
+
+	return address, nil
+}
+
+func keccak256(data []byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+	_, _ = hasher.Write(data)
+
+	return hasher.Sum(nil)
+}
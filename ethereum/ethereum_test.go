@@ -0,0 +1,93 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// Accounts of the default Hardhat and Anvil development mnemonic.
+const hardhatMnemonic = "test test test test test test test test test test test junk"
+
+var hardhatAccounts = []struct {
+	privateKey string
+	address    string
+}{
+	{"ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+	{"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d", "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+	{"5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a", "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"},
+	{"7c852118294e51e653712a81e05800f419141751be58f605c371e15141b007a6", "0x90F79bf6EB2c4f870365E785982E1f101E93b906"},
+	{"47e179ec197488593b187f80a00eb0da91f1b9d0b13f8733639f19c30a34926a", "0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65"},
+}
+
+func TestNewAccount(t *testing.T) {
+	seed, err := bip39.NewSeedWithErrorChecking("english", hardhatMnemonic, "")
+	assert.Nil(t, err)
+
+	for i, vector := range hardhatAccounts {
+		account, err := NewAccount(seed, uint32(i))
+		assert.Nil(t, err)
+
+		assert.EqualString(t, Path(uint32(i)), account.Path)
+		assert.EqualString(t, vector.privateKey, hex.EncodeToString(account.PrivateKey))
+		assert.EqualString(t, vector.address, account.Address)
+		assert.Equal(t, 65, len(account.PublicKey))
+		assert.Equal(t, byte(0x04), account.PublicKey[0])
+	}
+
+	_, err = DeriveAccount(seed, "44'/60'")
+	assert.NotNil(t, err)
+}
+
+func TestPublicKeyToAddress(t *testing.T) {
+	seed := bip39.NewSeed(hardhatMnemonic, "")
+	account, err := NewAccount(seed, 0)
+	assert.Nil(t, err)
+
+	address, err := PublicKeyToAddress(account.PublicKey)
+	assert.Nil(t, err)
+	assert.EqualString(t, account.Address, address)
+
+	compressed := append([]byte{0x02}, account.PublicKey[1:33]...)
+	for _, publicKey := range [][]byte{nil, {}, {0x04}, compressed, append([]byte{0x03}, account.PublicKey[1:]...), append(account.PublicKey, 0)} {
+		_, err := PublicKeyToAddress(publicKey)
+		assert.Equal(t, ErrInvalidPublicKey, err)
+	}
+}
+
+func TestChecksumAddress(t *testing.T) {
+	// Test vectors from EIP-55.
+	for _, address := range []string{
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x27b1fdb04752bbc536007a920d24acb045561c26",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		decoded, err := ParseAddress(address)
+		assert.Nil(t, err)
+		assert.EqualString(t, address, ChecksumAddress(decoded))
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	address, err := ParseAddress(strings.ToLower(hardhatAccounts[0].address))
+	assert.Nil(t, err)
+	assert.EqualString(t, hardhatAccounts[0].address, ChecksumAddress(address))
+
+	for _, s := range []string{
+		"0xf39fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"f39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb922",
+		"0xg39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+	} {
+		_, err := ParseAddress(s)
+		assert.Equal(t, ErrInvalidAddress, err)
+	}
+}
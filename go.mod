@@ -3,6 +3,12 @@ module github.com/decen-one/go-bip39
 go 1.20
 
 require (
+	filippo.io/edwards25519 v1.1.0
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.1
 	golang.org/x/crypto v0.10.0
+	golang.org/x/term v0.9.0
 	golang.org/x/text v0.10.0
+	rsc.io/qr v0.2.0
 )
+
+require golang.org/x/sys v0.9.0 // indirect
@@ -0,0 +1,119 @@
+package bip39
+
+import (
+	"crypto/subtle"
+	"sync/atomic"
+)
+
+// maxEntryWidth is the largest entry of a word table, one more than the
+// longest word of all the wordlists.
+const maxEntryWidth = 64
+
+// constantTime is 1 when words are looked up in constant time.
+var constantTime int32
+
+// wordTables holds the wordlists in fixed size entries per each language.
+var wordTables = map[string]*wordTable{}
+
+// SetConstantTimeLookup sets whether mnemonics are decoded in a side channel
+// hardened mode, where each word is mapped to its index by scanning the whole
+// wordlist in constant time instead of with a map lookup, and all the words
+// are looked up even after an unknown one. The checksum is always compared in
+// constant time.
+//
+// The lookup time of a word does not depend on the word, but the time to
+// split a mnemonic into words still depends on its length. An unknown word is
+// reported as ErrInvalidMnemonic in this mode.
+func SetConstantTimeLookup(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&constantTime, 1)
+	} else {
+		atomic.StoreInt32(&constantTime, 0)
+	}
+}
+
+// wordTable is a wordlist stored in entries of the same width, each holding
+// the length of a word followed by the word and zero padding, so that a word
+// can be compared with every entry in the same time.
+type wordTable struct {
+	width   int
+	entries []byte
+}
+
+func newWordTable(list []string) *wordTable {
+	width := 0
+	for _, word := range list {
+		if len(word)+1 > width {
+			width = len(word) + 1
+		}
+	}
+	if width > maxEntryWidth {
+		panic("bip39: word too long for a word table")
+	}
+
+	t := &wordTable{width: width, entries: make([]byte, len(list)*width)}
+	for i, word := range list {
+		entry := t.entries[i*width : (i+1)*width]
+		entry[0] = byte(len(word))
+		copy(entry[1:], word)
+	}
+
+	return t
+}
+
+// lookup returns the index of word and 1 if the word is in the table, or 0
+// and 0 otherwise. It compares the word with every entry of the table.
+func (t *wordTable) lookup(word []byte) (int, int) {
+	var buf [maxEntryWidth]byte
+	defer wipe(buf[:])
+
+	// A word too long for the table is truncated and never matches, as its
+	// length byte can not be equal to the length of an entry.
+	candidate := buf[:t.width]
+	fits := subtle.ConstantTimeLessOrEq(len(word), t.width-1)
+	candidate[0] = byte(subtle.ConstantTimeSelect(fits, len(word), 0xff))
+	copy(candidate[1:], word)
+
+	index, found := 0, 0
+	for i := 0; i < len(t.entries)/t.width; i++ {
+		equal := subtle.ConstantTimeCompare(t.entries[i*t.width:(i+1)*t.width], candidate)
+		index = subtle.ConstantTimeSelect(equal, i, index)
+		found |= equal
+	}
+
+	return index, found & fits
+}
+
+// decodeWords stores in entropy the entropy encoded by words. It returns the
+// position of the first unknown word, or -1 if all the words are known, and
+// whether the checksum is correct.
+func decodeWords(lang string, words [][]byte, entropy []byte) (int, bool) {
+	var indexes [maxWords]int
+	defer wipeIndexes(indexes[:])
+
+	if atomic.LoadInt32(&constantTime) == 1 {
+		unknown := -1
+		table := wordTables[lang]
+		for i, word := range words {
+			index, found := table.lookup(word)
+			indexes[i] = index
+			if found == 0 && unknown < 0 {
+				unknown = i
+			}
+		}
+		if unknown >= 0 {
+			return unknown, false
+		}
+	} else {
+		for i, word := range words {
+			// Indexing a map with a converted byte slice does not copy it.
+			index, found := wordMap[lang][string(word)]
+			if !found {
+				return i, false
+			}
+			indexes[i] = index
+		}
+	}
+
+	return -1, decodeIndexes(indexes[:len(words)], entropy)
+}
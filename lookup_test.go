@@ -0,0 +1,133 @@
+package bip39
+
+import (
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decen-one/go-bip39/assert"
+)
+
+func TestWordTable(t *testing.T) {
+	for lang, list := range wordList {
+		table := wordTables[lang]
+		for i, word := range list {
+			index, found := table.lookup([]byte(word))
+			assert.Equal(t, 1, found)
+			assert.Equal(t, i, index)
+		}
+	}
+
+	table := wordTables["english"]
+	for _, word := range []string{"", "abando", "abandonx", "Abandon", "abandon\x00", strings.Repeat("x", 100)} {
+		index, found := table.lookup([]byte(word))
+		assert.Equal(t, 0, found)
+		assert.Equal(t, 0, index)
+	}
+}
+
+func TestConstantTimeLookup(t *testing.T) {
+	SetConstantTimeLookup(true)
+	defer SetConstantTimeLookup(false)
+
+	for _, vector := range testVectors() {
+		entropy, err := EntropyFromMnemonic(vector.lang, vector.mnemonic)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.entropy, hex.EncodeToString(entropy))
+
+		secret, err := EntropyFromMnemonicSecret(vector.lang, []byte(vector.mnemonic))
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.entropy, hex.EncodeToString(secret.Bytes()))
+	}
+
+	for mnemonic, expected := range map[string]error{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon zzzzzzz": ErrInvalidMnemonic,
+		"zzzzzzz abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon": ErrInvalidMnemonic,
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon": ErrChecksumIncorrect,
+	} {
+		_, err := EntropyFromMnemonic("english", mnemonic)
+		assert.Equal(t, expected, err)
+
+		_, err = EntropyFromMnemonicSecret("english", []byte(mnemonic))
+		assert.Equal(t, expected, err)
+	}
+
+	SetConstantTimeLookup(false)
+	_, err := EntropyFromMnemonic("english", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon zzzzzzz")
+	assert.EqualString(t, "word `zzzzzzz` not found in reverse map", err.Error())
+}
+
+// TestLookupTimingVariance checks that looking up the first and the last
+// word of the wordlist, words of other lengths and unknown words takes the
+// same time. Each word is timed over many rounds, and the fastest round is
+// kept to filter out the noise of the machine. A lookup stopping at the
+// matching entry would take thousands of times longer for the last word than
+// for the first one, so the tolerance leaves room for a busy machine.
+//
+// Timings depend on the machine, so the test only runs with
+// BIP39_TIMING_TESTS=1, as in `make timing_tests`.
+func TestLookupTimingVariance(t *testing.T) {
+	if os.Getenv("BIP39_TIMING_TESTS") != "1" {
+		t.Skip("set BIP39_TIMING_TESTS=1 to run timing tests")
+	}
+
+	table := wordTables["english"]
+	words := []string{"abandon", "zoo", "satoshi", "abstract", "zzz", "notaword", strings.Repeat("x", 40)}
+
+	fastest := make([]time.Duration, len(words))
+	for round := 0; round < 200; round++ {
+		for i, word := range words {
+			b := []byte(word)
+			start := time.Now()
+			for n := 0; n < 20; n++ {
+				table.lookup(b)
+			}
+			if elapsed := time.Since(start); round == 0 || elapsed < fastest[i] {
+				fastest[i] = elapsed
+			}
+		}
+	}
+
+	min, max := fastest[0], fastest[0]
+	for _, d := range fastest {
+		if d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+	}
+	if float64(max) > 1.5*float64(min) {
+		t.Errorf("Lookup times vary from %v to %v: %v", min, max, fastest)
+	}
+}
+
+func BenchmarkEntropyFromMnemonic(b *testing.B) {
+	mnemonic := testVectors()[3].mnemonic
+	b.Run("map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = EntropyFromMnemonic("english", mnemonic)
+		}
+	})
+	b.Run("constant-time", func(b *testing.B) {
+		SetConstantTimeLookup(true)
+		defer SetConstantTimeLookup(false)
+		for i := 0; i < b.N; i++ {
+			_, _ = EntropyFromMnemonic("english", mnemonic)
+		}
+	})
+}
+
+func BenchmarkWordLookup(b *testing.B) {
+	table := wordTables["english"]
+	for _, word := range []string{"abandon", "zoo", "notaword"} {
+		word := []byte(word)
+		b.Run(string(word), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				table.lookup(word)
+			}
+		})
+	}
+}
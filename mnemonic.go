@@ -0,0 +1,264 @@
+package bip39
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// redacted is printed and encoded in place of a mnemonic or a seed.
+const redacted = "[REDACTED]"
+
+// Mnemonic is a valid mnemonic that is redacted when it is printed with fmt,
+// encoded to JSON or text, or logged with log/slog, so that it can not leak
+// by accident. Its words are only available through Reveal.
+//
+// A Mnemonic holds its words in a Secret, which Wipe zeroes once it is no
+// longer needed. Copies of a Mnemonic share their words.
+type Mnemonic struct {
+	lang  string
+	words *Secret
+}
+
+// MnemonicFromEntropy is like NewMnemonic but returns a Mnemonic.
+func MnemonicFromEntropy(lang string, entropy []byte) (Mnemonic, error) {
+	words, err := NewMnemonicSecret(lang, entropy)
+	if err != nil {
+		return Mnemonic{}, err
+	}
+
+	return Mnemonic{lang: checkLanguage(lang), words: words}, nil
+}
+
+// RandMnemonic is like NewRandMnemonic but returns a Mnemonic.
+func RandMnemonic(lang string, mnemonicSize int) (Mnemonic, error) {
+	entropy, err := NewEntropyWithMnemonicSize(mnemonicSize)
+	if err != nil {
+		return Mnemonic{}, err
+	}
+	defer wipe(entropy)
+
+	return MnemonicFromEntropy(lang, entropy)
+}
+
+// ParseMnemonic checks a mnemonic and returns it as a Mnemonic, with its
+// words separated by single spaces. Unlike EntropyFromMnemonic, the error of
+// an unknown word does not include the word.
+func ParseMnemonic(lang string, mnemonic string) (Mnemonic, error) {
+	b := []byte(mnemonic)
+	defer wipe(b)
+
+	return ParseMnemonicBytes(lang, b)
+}
+
+// ParseMnemonicBytes is like ParseMnemonic for a mnemonic held in a byte
+// slice.
+func ParseMnemonicBytes(lang string, mnemonic []byte) (Mnemonic, error) {
+	entropy, err := EntropyFromMnemonicSecret(lang, mnemonic)
+	if err != nil {
+		return Mnemonic{}, err
+	}
+	defer entropy.Wipe()
+
+	return MnemonicFromEntropy(lang, entropy.Bytes())
+}
+
+// Language returns the language of the mnemonic.
+func (m Mnemonic) Language() string {
+	return m.lang
+}
+
+// Reveal returns the words of the mnemonic. The returned string can not be
+// wiped.
+func (m Mnemonic) Reveal() string {
+	if m.words == nil {
+		return ""
+	}
+
+	return string(m.words.Bytes())
+}
+
+// Entropy is like EntropyFromMnemonic but returns the entropy in a Secret.
+func (m Mnemonic) Entropy() (*Secret, error) {
+	if m.words == nil {
+		return nil, ErrInvalidMnemonic
+	}
+
+	return EntropyFromMnemonicSecret(m.lang, m.words.Bytes())
+}
+
+// EntropyWithChecksum is like MnemonicToByteArray but returns the entropy
+// followed by its checksum in a Secret.
+func (m Mnemonic) EntropyWithChecksum() (*Secret, error) {
+	entropy, err := m.Entropy()
+	if err != nil {
+		return nil, err
+	}
+	defer entropy.Wipe()
+
+	b := addChecksum(entropy.Bytes())
+	defer wipe(b)
+
+	return NewSecretFromBytes(b), nil
+}
+
+// Seed is like NewSeed but returns a Seed.
+func (m Mnemonic) Seed(password string) Seed {
+	p := []byte(password)
+	defer wipe(p)
+
+	return Seed{secret: NewSeedSecret(m.bytes(), p)}
+}
+
+// SeedContext is like NewSeedContext but returns a Seed.
+func (m Mnemonic) SeedContext(ctx context.Context, password string) (Seed, error) {
+	deriver := newSeedDeriver(m.bytes())
+	defer deriver.Wipe()
+
+	seed, err := deriver.SeedContext(ctx, password)
+	if err != nil {
+		return Seed{}, err
+	}
+
+	return newSeed(seed), nil
+}
+
+// SeedsContext is like NewSeedsContext but returns Seeds.
+func (m Mnemonic) SeedsContext(ctx context.Context, passwords []string, workers int) ([]Seed, error) {
+	seeds, err := newSeedsContext(ctx, m.bytes(), passwords, workers)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Seed, len(seeds))
+	for i, seed := range seeds {
+		result[i] = newSeed(seed)
+	}
+
+	return result, nil
+}
+
+// Deriver is like NewSeedDeriver for the mnemonic.
+func (m Mnemonic) Deriver() *SeedDeriver {
+	return newSeedDeriver(m.bytes())
+}
+
+// Fingerprint is like Fingerprint for the mnemonic.
+func (m Mnemonic) Fingerprint(password string) ([]byte, error) {
+	seed := m.Seed(password)
+	defer seed.Wipe()
+
+	return seedFingerprint(seed.Bytes())
+}
+
+// IsFingerprintMatching is like IsFingerprintMatching for the mnemonic.
+func (m Mnemonic) IsFingerprintMatching(password string, fingerprint string) (bool, error) {
+	return isFingerprintMatching(fingerprint, func() ([]byte, error) {
+		return m.Fingerprint(password)
+	})
+}
+
+// Wipe zeroes the words of the mnemonic and of all its copies.
+func (m Mnemonic) Wipe() {
+	if m.words != nil {
+		m.words.Wipe()
+	}
+}
+
+// String returns a redacted placeholder.
+func (m Mnemonic) String() string {
+	return redacted
+}
+
+// GoString returns a redacted placeholder.
+func (m Mnemonic) GoString() string {
+	return "bip39.Mnemonic{" + redacted + "}"
+}
+
+// Format prints a redacted placeholder, or the GoString for %#v.
+func (m Mnemonic) Format(f fmt.State, verb rune) {
+	format(f, verb, m)
+}
+
+// MarshalJSON returns a redacted placeholder as a JSON string.
+func (m Mnemonic) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// MarshalText returns a redacted placeholder.
+func (m Mnemonic) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+func (m Mnemonic) bytes() []byte {
+	if m.words == nil {
+		return nil
+	}
+
+	return m.words.Bytes()
+}
+
+// Seed is a seed that is redacted like a Mnemonic. Its bytes are only
+// available through Bytes.
+type Seed struct {
+	secret *Secret
+}
+
+// newSeed returns a Seed holding a copy of seed, and wipes seed.
+func newSeed(seed []byte) Seed {
+	defer wipe(seed)
+
+	return Seed{secret: NewSecretFromBytes(seed)}
+}
+
+// Bytes returns the seed, which is only valid until the seed is wiped.
+func (s Seed) Bytes() []byte {
+	if s.secret == nil {
+		return nil
+	}
+
+	return s.secret.Bytes()
+}
+
+// Wipe zeroes the seed and all its copies.
+func (s Seed) Wipe() {
+	if s.secret != nil {
+		s.secret.Wipe()
+	}
+}
+
+// String returns a redacted placeholder.
+func (s Seed) String() string {
+	return redacted
+}
+
+// GoString returns a redacted placeholder.
+func (s Seed) GoString() string {
+	return "bip39.Seed{" + redacted + "}"
+}
+
+// Format prints a redacted placeholder, or the GoString for %#v.
+func (s Seed) Format(f fmt.State, verb rune) {
+	format(f, verb, s)
+}
+
+// MarshalJSON returns a redacted placeholder as a JSON string.
+func (s Seed) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// MarshalText returns a redacted placeholder.
+func (s Seed) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// format prints the GoString of a redacted value for %#v, and the redacted
+// placeholder for all the other verbs.
+func format(f fmt.State, verb rune, v fmt.GoStringer) {
+	if verb == 'v' && f.Flag('#') {
+		_, _ = io.WriteString(f, v.GoString())
+		return
+	}
+
+	_, _ = io.WriteString(f, redacted)
+}
@@ -0,0 +1,157 @@
+package bip39
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+)
+
+func TestMnemonicRedaction(t *testing.T) {
+	vector := testVectors()[0]
+	mnemonic, err := ParseMnemonic(vector.lang, vector.mnemonic)
+	assert.Nil(t, err)
+	seed := mnemonic.Seed(vector.password)
+
+	type wallet struct {
+		Name     string
+		Mnemonic Mnemonic
+		Seed     *Seed
+	}
+	w := wallet{Name: "savings", Mnemonic: mnemonic, Seed: &seed}
+
+	var outputs []string
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%q", "%x", "%X", "%d", "%20s", "%T"} {
+		outputs = append(outputs,
+			fmt.Sprintf(format, mnemonic), fmt.Sprintf(format, &mnemonic),
+			fmt.Sprintf(format, seed), fmt.Sprintf(format, w), fmt.Sprintf(format, &w))
+	}
+	outputs = append(outputs, fmt.Sprint(mnemonic, seed), fmt.Sprintln(mnemonic, seed), mnemonic.String(), seed.GoString())
+
+	for _, v := range []interface{}{mnemonic, seed, w, &w} {
+		data, err := json.Marshal(v)
+		assert.Nil(t, err)
+		outputs = append(outputs, string(data))
+
+		data, err = xml.Marshal(v)
+		if err == nil {
+			outputs = append(outputs, string(data))
+		}
+	}
+
+	text, err := mnemonic.MarshalText()
+	assert.Nil(t, err)
+	outputs = append(outputs, string(text))
+	text, err = seed.MarshalText()
+	assert.Nil(t, err)
+	outputs = append(outputs, string(text))
+
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	logger.Printf("restoring %v with %s", mnemonic, seed)
+	logger.Println(w)
+	outputs = append(outputs, buf.String())
+
+	seedHex := hex.EncodeToString(seed.Bytes())
+	for _, output := range outputs {
+		assert.False(t, strings.Contains(output, "abandon"))
+		assert.False(t, strings.Contains(strings.ToLower(output), seedHex[:8]))
+		assert.False(t, strings.Contains(output, string(seed.Bytes()[:4])))
+	}
+
+	assert.EqualString(t, "[REDACTED]", fmt.Sprint(mnemonic))
+	assert.EqualString(t, "bip39.Mnemonic{[REDACTED]}", fmt.Sprintf("%#v", mnemonic))
+	data, err := json.Marshal(w)
+	assert.Nil(t, err)
+	assert.EqualString(t, `{"Name":"savings","Mnemonic":"[REDACTED]","Seed":"[REDACTED]"}`, string(data))
+}
+
+func TestMnemonicTypes(t *testing.T) {
+	for _, vector := range testVectors() {
+		entropy, _ := hex.DecodeString(vector.entropy)
+
+		mnemonic, err := MnemonicFromEntropy(vector.lang, entropy)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.mnemonic, mnemonic.Reveal())
+		assert.EqualString(t, vector.lang, mnemonic.Language())
+
+		parsed, err := ParseMnemonic(strings.ToUpper(vector.lang), "  "+strings.ReplaceAll(vector.mnemonic, " ", "\t ")+"\n")
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.mnemonic, parsed.Reveal())
+
+		decoded, err := parsed.Entropy()
+		assert.Nil(t, err)
+		assert.EqualByteSlices(t, entropy, decoded.Bytes())
+
+		withChecksum, err := parsed.EntropyWithChecksum()
+		assert.Nil(t, err)
+		expected, err := MnemonicToByteArray(vector.lang, vector.mnemonic)
+		assert.Nil(t, err)
+		assert.EqualByteSlices(t, expected, withChecksum.Bytes())
+
+		assert.EqualString(t, vector.seed, hex.EncodeToString(parsed.Seed(vector.password).Bytes()))
+		assert.EqualString(t, vector.seed, hex.EncodeToString(parsed.Deriver().Seed(vector.password)))
+	}
+
+	mnemonic, err := ParseMnemonic("english", fingerprintVectors()[0].mnemonic)
+	assert.Nil(t, err)
+
+	fingerprint, err := mnemonic.Fingerprint("")
+	assert.Nil(t, err)
+	assert.EqualString(t, "73c5da0a", hex.EncodeToString(fingerprint))
+	matching, err := mnemonic.IsFingerprintMatching("", "73c5da0a")
+	assert.Nil(t, err)
+	assert.True(t, matching)
+	_, err = mnemonic.IsFingerprintMatching("", "73c5da")
+	assert.Equal(t, ErrInvalidFingerprint, err)
+
+	seed, err := mnemonic.SeedContext(context.Background(), "TREZOR")
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, NewSeed(mnemonic.Reveal(), "TREZOR"), seed.Bytes())
+
+	seeds, err := mnemonic.SeedsContext(context.Background(), []string{"", "TREZOR"}, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(seeds))
+	assert.EqualByteSlices(t, seed.Bytes(), seeds[1].Bytes())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = mnemonic.SeedContext(ctx, "TREZOR")
+	assert.Equal(t, context.Canceled, err)
+
+	random, err := RandMnemonic("english", 24)
+	assert.Nil(t, err)
+	assert.Equal(t, 24, len(strings.Fields(random.Reveal())))
+	_, err = RandMnemonic("english", 13)
+	assert.Equal(t, ErrMnemonicSizeInvalid, err)
+
+	// The error of an unknown word does not reveal the mnemonic.
+	_, err = ParseMnemonic("english", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon secretword")
+	assert.Equal(t, ErrInvalidMnemonic, err)
+
+	// Wiping a copy wipes the words shared by all copies.
+	words := mnemonic.bytes()
+	copied := mnemonic
+	copied.Wipe()
+	assert.True(t, isZero(words))
+	assert.EqualString(t, "", mnemonic.Reveal())
+
+	seedBytes := seed.Bytes()
+	seed.Wipe()
+	assert.True(t, isZero(seedBytes))
+	assert.Equal(t, 0, len(seed.Bytes()))
+
+	var zero Mnemonic
+	assert.EqualString(t, "", zero.Reveal())
+	_, err = zero.Entropy()
+	assert.Equal(t, ErrInvalidMnemonic, err)
+	zero.Wipe()
+	Seed{}.Wipe()
+}
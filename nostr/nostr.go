@@ -0,0 +1,136 @@
+// Package nostr derives Nostr keys from a mnemonic as specified by NIP-06 and
+// encodes them with the NIP-19 nsec and npub bech32 formats.
+package nostr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/bech32"
+	"github.com/decen-one/go-bip39/bip32"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Human readable parts of the NIP-19 encodings.
+const (
+	PrivateKeyPrefix = "nsec"
+	PublicKeyPrefix  = "npub"
+)
+
+var (
+	// ErrInvalidKey is returned when trying to use a malformed key.
+	ErrInvalidKey = errors.New("Invalid key")
+
+	// ErrInvalidPrefix is returned when decoding a bech32 string with an
+	// unexpected human readable part.
+	ErrInvalidPrefix = errors.New("Invalid bech32 prefix")
+)
+
+// Keys is a Nostr key pair.
+type Keys struct {
+	// PrivateKey is the 32 byte secp256k1 private key.
+	PrivateKey []byte
+
+	// PublicKey is the 32 byte x-only public key.
+	PublicKey []byte
+}
+
+// Path returns the NIP-06 derivation path of the given account.
+func Path(account uint32) string {
+	return fmt.Sprintf("m/44'/1237'/%d'/0/0", account)
+}
+
+// NewKeys derives the keys of the given account from a mnemonic and
+// passphrase.
+func NewKeys(lang string, mnemonic string, passphrase string, account uint32) (*Keys, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(lang, mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := master.Derive(Path(account))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeysFromPrivateKey(key.Key)
+}
+
+// NewKeysFromPrivateKey creates the key pair of a 32 byte private key.
+func NewKeysFromPrivateKey(privateKey []byte) (*Keys, error) {
+	var k secp256k1.ModNScalar
+	if len(privateKey) != 32 || k.SetByteSlice(privateKey) || k.IsZero() {
+		return nil, ErrInvalidKey
+	}
+
+	publicKey := secp256k1.PrivKeyFromBytes(privateKey).PubKey().SerializeCompressed()
+
+	return &Keys{PrivateKey: privateKey, PublicKey: publicKey[1:]}, nil
+}
+
+// Nsec returns the NIP-19 encoding of the private key.
+func (k *Keys) Nsec() string {
+	return encode(PrivateKeyPrefix, k.PrivateKey)
+}
+
+// Npub returns the NIP-19 encoding of the public key.
+func (k *Keys) Npub() string {
+	return encode(PublicKeyPrefix, k.PublicKey)
+}
+
+// DecodeNsec decodes a NIP-19 nsec string into its key pair.
+func DecodeNsec(s string) (*Keys, error) {
+	privateKey, err := decode(PrivateKeyPrefix, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeysFromPrivateKey(privateKey)
+}
+
+// DecodeNpub decodes a NIP-19 npub string into its 32 byte x-only public key.
+func DecodeNpub(s string) ([]byte, error) {
+	publicKey, err := decode(PublicKeyPrefix, s)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key must be the X coordinate of a point on the curve.
+	if _, err := secp256k1.ParsePubKey(append([]byte{0x02}, publicKey...)); err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	return publicKey, nil
+}
+
+func encode(prefix string, key []byte) string {
+	data, _ := bech32.ConvertBits(key, 8, 5, true) // err is always nil when padding
+
+	return bech32.Encode(prefix, data, bech32.Bech32)
+}
+
+func decode(prefix string, s string) ([]byte, error) {
+	hrp, data, variant, err := bech32.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != prefix || variant != bech32.Bech32 {
+		return nil, ErrInvalidPrefix
+	}
+
+	key, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	return key, nil
+}
@@ -0,0 +1,74 @@
+package nostr
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// Test vectors from https://github.com/nostr-protocol/nips/blob/master/06.md
+var testVectors = []struct {
+	mnemonic   string
+	privateKey string
+	nsec       string
+	publicKey  string
+	npub       string
+}{
+	{
+		mnemonic:   "leader monkey parrot ring guide accident before fence cannon height naive bean",
+		privateKey: "7f7ff03d123792d6ac594bfa67bf6d0c0ab55b6b1fdb6249303fe861f1ccba9a",
+		nsec:       "nsec10allq0gjx7fddtzef0ax00mdps9t2kmtrldkyjfs8l5xruwvh2dq0lhhkp",
+		publicKey:  "17162c921dc4d2518f9a101db33695df1afb56ab82f5ff3e5da6eec3ca5cd917",
+		npub:       "npub1zutzeysacnf9rru6zqwmxd54mud0k44tst6l70ja5mhv8jjumytsd2x7nu",
+	},
+	{
+		mnemonic:   "what bleak badge arrange retreat wolf trade produce cricket blur garlic valid proud rude strong choose busy staff weather area salt hollow arm fade",
+		privateKey: "c15d739894c81a2fcfd3a2df85a0d2c0dbc47a280d092799f144d73d7ae78add",
+		nsec:       "nsec1c9wh8xy5eqdzln7n5t0ctgxjcrdug73gp5yj0x03gntn67h83twssdfhel",
+		publicKey:  "d41b22899549e1f3d335a31002cfd382174006e166d3e658e3a5eecdb6463573",
+		npub:       "npub16sdj9zv4f8sl85e45vgq9n7nsgt5qphpvmf7vk8r5hhvmdjxx4es8rq74h",
+	},
+}
+
+func TestNewKeys(t *testing.T) {
+	for _, vector := range testVectors {
+		keys, err := NewKeys("english", vector.mnemonic, "", 0)
+		assert.Nil(t, err)
+
+		assert.EqualString(t, vector.privateKey, hex.EncodeToString(keys.PrivateKey))
+		assert.EqualString(t, vector.publicKey, hex.EncodeToString(keys.PublicKey))
+		assert.EqualString(t, vector.nsec, keys.Nsec())
+		assert.EqualString(t, vector.npub, keys.Npub())
+	}
+
+	// Accounts derive distinct keys.
+	keys, err := NewKeys("english", testVectors[0].mnemonic, "", 1)
+	assert.Nil(t, err)
+	assert.False(t, hex.EncodeToString(keys.PrivateKey) == testVectors[0].privateKey)
+
+	_, err = NewKeys("english", "leader monkey parrot", "", 0)
+	assert.NotNil(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	for _, vector := range testVectors {
+		keys, err := DecodeNsec(vector.nsec)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.privateKey, hex.EncodeToString(keys.PrivateKey))
+		assert.EqualString(t, vector.publicKey, hex.EncodeToString(keys.PublicKey))
+
+		publicKey, err := DecodeNpub(vector.npub)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.publicKey, hex.EncodeToString(publicKey))
+
+		_, err = DecodeNsec(vector.npub)
+		assert.Equal(t, ErrInvalidPrefix, err)
+
+		_, err = DecodeNpub(vector.nsec)
+		assert.Equal(t, ErrInvalidPrefix, err)
+	}
+
+	_, err := DecodeNpub("npub1zutzeysacnf9rru6zqwmxd54mud0k44tst6l70ja5mhv8jjumytsd2x7nv")
+	assert.NotNil(t, err)
+}
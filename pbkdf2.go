@@ -0,0 +1,207 @@
+package bip39
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding"
+	"hash"
+	"runtime"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// seedPRF is HMAC-SHA512 with the hash states after the inner and the outer
+// key pads computed once and reused by every seed of a mnemonic. Each PBKDF2
+// iteration still costs two SHA512 compressions, as with crypto/hmac, which
+// also saves the pad states: the SHA512 assembly of the standard library is
+// faster than a compression written in Go, so seeds are not derived faster
+// than with x/crypto/pbkdf2, only with fewer allocations.
+type seedPRF struct {
+	inner, outer           hash.Hash
+	innerReset, outerReset encoding.BinaryUnmarshaler
+	innerState, outerState []byte
+}
+
+func newSeedPRF(key []byte) *seedPRF {
+	var digest [sha512.Size]byte
+	defer wipe(digest[:])
+	if len(key) > sha512.BlockSize {
+		digest = sha512.Sum512(key)
+		key = digest[:]
+	}
+
+	ipad := make([]byte, sha512.BlockSize)
+	opad := make([]byte, sha512.BlockSize)
+	defer wipe(ipad)
+	defer wipe(opad)
+	copy(ipad, key)
+	copy(opad, key)
+	for i := range ipad {
+		ipad[i] ^= 0x36
+		opad[i] ^= 0x5c
+	}
+
+	p := &seedPRF{inner: sha512.New(), outer: sha512.New()}
+	_, _ = p.inner.Write(ipad)
+	_, _ = p.outer.Write(opad)
+
+	// The SHA512 digests implement encoding.BinaryMarshaler and
+	// encoding.BinaryUnmarshaler. Marshaling never fails, and neither does
+	// restoring the states it returns.
+	p.innerState, _ = p.inner.(encoding.BinaryMarshaler).MarshalBinary()
+	p.outerState, _ = p.outer.(encoding.BinaryMarshaler).MarshalBinary()
+	p.innerReset = p.inner.(encoding.BinaryUnmarshaler)
+	p.outerReset = p.outer.(encoding.BinaryUnmarshaler)
+
+	return p
+}
+
+// sum appends the HMAC of message to dst[:0]. message may alias dst.
+func (p *seedPRF) sum(dst []byte, message []byte) []byte {
+	_ = p.innerReset.UnmarshalBinary(p.innerState)
+	_, _ = p.inner.Write(message)
+	dst = p.inner.Sum(dst[:0])
+
+	_ = p.outerReset.UnmarshalBinary(p.outerState)
+	_, _ = p.outer.Write(dst)
+	return p.outer.Sum(dst[:0])
+}
+
+// wipe zeroes the pad states and the buffered messages of the digests.
+func (p *seedPRF) wipe() {
+	// The saved states have empty buffers, so restoring them zeroes the
+	// buffered messages before resetting the chaining values.
+	_ = p.innerReset.UnmarshalBinary(p.innerState)
+	_ = p.outerReset.UnmarshalBinary(p.outerState)
+	p.inner.Reset()
+	p.outer.Reset()
+
+	wipe(p.innerState)
+	wipe(p.outerState)
+}
+
+// SeedDeriver derives the seeds of a mnemonic with different passwords. It
+// normalizes the mnemonic and prepares the HMAC keyed with it once, and
+// reuses its buffers, which makes it suited to trying many passwords. The
+// time of each seed is that of NewSeed. A SeedDeriver is not safe for
+// concurrent use.
+type SeedDeriver struct {
+	prf  *seedPRF
+	salt []byte
+	u    []byte
+}
+
+// NewSeedDeriver returns a SeedDeriver for a mnemonic. No checking is
+// performed to validate that the string provided is a valid mnemonic.
+func NewSeedDeriver(mnemonic string) *SeedDeriver {
+	b := []byte(mnemonic)
+	defer wipe(b)
+
+	return newSeedDeriver(b)
+}
+
+func newSeedDeriver(mnemonic []byte) *SeedDeriver {
+	// norm.NFKD.Bytes may return its input, which must not be wiped.
+	key := norm.NFKD.Append(make([]byte, 0, len(mnemonic)), mnemonic...)
+	defer wipe(key)
+
+	return &SeedDeriver{
+		prf: newSeedPRF(key),
+		u:   make([]byte, 0, sha512.Size),
+	}
+}
+
+// Seed returns the seed of the mnemonic with a password, as NewSeed.
+func (d *SeedDeriver) Seed(password string) []byte {
+	seed, _ := d.SeedContext(context.Background(), password) // err is always nil
+	return seed
+}
+
+// SeedContext is like Seed but stops and returns the context error as soon
+// as ctx is done.
+func (d *SeedDeriver) SeedContext(ctx context.Context, password string) ([]byte, error) {
+	b := []byte(password)
+	defer wipe(b)
+
+	seed := make([]byte, sha512.Size)
+	if err := d.derive(ctx, seed, b); err != nil {
+		return nil, err
+	}
+
+	return seed, nil
+}
+
+// SeedSecret is like Seed for a password held in a byte slice, and returns
+// the seed in a Secret.
+func (d *SeedDeriver) SeedSecret(password []byte) *Secret {
+	seed := NewSecret(sha512.Size)
+	_ = d.derive(context.Background(), seed.Bytes(), password) // err is always nil
+
+	return seed
+}
+
+// Wipe zeroes the state of the deriver, which derives the seeds of the
+// mnemonic without it. The deriver can not be used afterwards.
+func (d *SeedDeriver) Wipe() {
+	d.prf.wipe()
+	wipe(d.salt[:cap(d.salt)])
+	wipe(d.u[:cap(d.u)])
+}
+
+// nfkdLen returns the length of the NFKD normalization of b, which can be
+// 11 times that of b, as U+FDFA expands to 18 characters.
+func nfkdLen(b []byte) int {
+	var it norm.Iter
+	it.Init(norm.NFKD, b)
+
+	n := 0
+	for !it.Done() {
+		n += len(it.Next())
+	}
+
+	// The iterator buffers the normalized segments.
+	it = norm.Iter{}
+	runtime.KeepAlive(&it)
+
+	return n
+}
+
+// derive stores in seed the seed of the mnemonic with a password.
+func (d *SeedDeriver) derive(ctx context.Context, seed []byte, password []byte) error {
+	defer func() {
+		wipe(d.salt[:cap(d.salt)])
+		wipe(d.u[:cap(d.u)])
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Room for the normalized password, which is longer in some scripts, so
+	// that the salt buffer is not grown, leaving copies of the password.
+	if size := len("mnemonic") + nfkdLen(password) + 4; cap(d.salt) < size {
+		d.salt = make([]byte, 0, size)
+	}
+
+	// PBKDF2 with a single block, as the seed is as long as a SHA512 digest.
+	d.salt = norm.NFKD.Append(append(d.salt[:0], "mnemonic"...), password...)
+	d.salt = append(d.salt, 0, 0, 0, 1)
+	d.u = d.prf.sum(d.u, d.salt)
+	copy(seed, d.u)
+
+	for i := 1; i < seedIterations; i++ {
+		if i%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				wipe(seed)
+				return err
+			}
+		}
+
+		d.u = d.prf.sum(d.u, d.u)
+		for j := range seed {
+			seed[j] ^= d.u[j]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package bip39
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// referenceSeed is the seed of NewSeed computed with the generic PBKDF2.
+func referenceSeed(mnemonic string, password string) []byte {
+	return pbkdf2.Key(norm.NFKD.Bytes([]byte(mnemonic)), norm.NFKD.Bytes([]byte("mnemonic"+password)), seedIterations, 64, sha512.New)
+}
+
+func TestSeedDeriver(t *testing.T) {
+	for _, vector := range testVectors() {
+		seed := NewSeedDeriver(vector.mnemonic).Seed(vector.password)
+		assert.EqualString(t, vector.seed, hex.EncodeToString(seed))
+	}
+
+	// A deriver is reused across passwords of different lengths.
+	mnemonic := fingerprintVectors()[0].mnemonic
+	deriver := NewSeedDeriver(mnemonic)
+	for _, password := range []string{"", "TREZOR", "", strings.Repeat("long password ", 20), "パスワード"} {
+		assert.EqualByteSlices(t, referenceSeed(mnemonic, password), deriver.Seed(password))
+	}
+
+	// Mnemonics longer than the SHA512 block size are hashed into the key.
+	entropy, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000000")
+	long, err := NewMnemonic("japanese", entropy)
+	assert.Nil(t, err)
+	assert.True(t, len(norm.NFKD.String(long)) > sha512.BlockSize)
+	assert.EqualByteSlices(t, referenceSeed(long, "TREZOR"), NewSeedDeriver(long).Seed("TREZOR"))
+
+	// The salt buffer fits the normalized password, however much it expands,
+	// so it is never grown.
+	for _, password := range []string{"\ufdfa", strings.Repeat("\ufdfa", 50), "a" + strings.Repeat("\u0301", 100), "\u00bd\u2474"} {
+		size := len(norm.NFKD.String(password))
+		assert.Equal(t, size, nfkdLen([]byte(password)))
+
+		deriver := NewSeedDeriver(mnemonic)
+		assert.EqualByteSlices(t, referenceSeed(mnemonic, password), deriver.Seed(password))
+		assert.Equal(t, len("mnemonic")+size+4, cap(deriver.salt))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = deriver.SeedContext(ctx, "TREZOR")
+	assert.Equal(t, context.Canceled, err)
+	assert.EqualByteSlices(t, referenceSeed(mnemonic, "TREZOR"), deriver.Seed("TREZOR"))
+}
+
+func BenchmarkPBKDF2(b *testing.B) {
+	mnemonic := fingerprintVectors()[0].mnemonic
+	for i := 0; i < b.N; i++ {
+		referenceSeed(mnemonic, "TREZOR")
+	}
+}
+
+func BenchmarkNewSeed(b *testing.B) {
+	mnemonic := fingerprintVectors()[0].mnemonic
+	for i := 0; i < b.N; i++ {
+		NewSeed(mnemonic, "TREZOR")
+	}
+}
+
+func BenchmarkSeedDeriver(b *testing.B) {
+	deriver := NewSeedDeriver(fingerprintVectors()[0].mnemonic)
+	for i := 0; i < b.N; i++ {
+		deriver.Seed("TREZOR")
+	}
+}
@@ -0,0 +1,321 @@
+// Package recovery searches for a forgotten BIP39 passphrase of a known
+// mnemonic.
+//
+// A search derives the seed of every candidate passphrase of a search space,
+// such as a wordlist, a mask or the typos of a remembered guess, until one
+// matches a target known to belong to the wallet: its master key fingerprint,
+// an extended public key or its first address. The candidates are tried in
+// parallel, and a search reports its progress and can save checkpoints to
+// resume from after being cancelled.
+package recovery
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/decen-one/go-bip39"
+)
+
+// chunkSize is the number of consecutive candidates a worker tries at a time.
+const chunkSize = 64
+
+var (
+	// ErrNotFound is returned when no candidate of the search space matches
+	// the target.
+	ErrNotFound = errors.New("Passphrase not found")
+
+	// ErrCheckpointMismatch is returned when resuming from a checkpoint saved
+	// by a search for another target or in another search space.
+	ErrCheckpointMismatch = errors.New("Checkpoint belongs to another search")
+)
+
+// Progress is a snapshot of the progress of a search.
+type Progress struct {
+	// Tried is the number of candidates tried, including those tried before
+	// resuming from a checkpoint.
+	Tried uint64
+
+	// Total is the number of candidates in the search space.
+	Total uint64
+
+	// Elapsed is the time since the search started or resumed.
+	Elapsed time.Duration
+
+	// Rate is the number of candidates tried per second since the search
+	// started or resumed.
+	Rate float64
+}
+
+// Options configures a search. The zero value searches on all CPUs without
+// reporting progress or saving checkpoints.
+type Options struct {
+	// Workers is the number of goroutines deriving seeds. Zero or less uses
+	// GOMAXPROCS.
+	Workers int
+
+	// Progress, if not nil, is called every ProgressInterval and once when
+	// the search ends.
+	Progress func(Progress)
+
+	// ProgressInterval is the interval between progress reports and
+	// checkpoints. Zero uses one second.
+	ProgressInterval time.Duration
+
+	// Checkpoint, if not empty, is the path of a file where the search saves
+	// its position every ProgressInterval and when it ends. A search resumes
+	// from the checkpoint if the file exists. The checkpoint holds the
+	// position, a description of the target and a digest of the search
+	// space keyed with the mnemonic, never the mnemonic or a candidate.
+	Checkpoint string
+}
+
+// checkpoint is the content of a checkpoint file. All the candidates before
+// Next have been tried.
+type checkpoint struct {
+	Target string `json:"target"`
+	Space  string `json:"space"`
+	Total  uint64 `json:"total"`
+	Next   uint64 `json:"next"`
+}
+
+// Search returns the first passphrase of a search space whose seed with the
+// mnemonic matches the target. It returns ErrNotFound if the search space is
+// exhausted, and the context error if ctx is done first.
+func Search(ctx context.Context, lang string, mnemonic string, target Target, space Space, opts Options) (string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return "", err
+	}
+	defer wipe(entropy)
+
+	total := space.Len()
+	var next uint64
+	var digest string
+	if opts.Checkpoint != "" {
+		digest = spaceDigest(lang, entropy, space)
+		if next, err = readCheckpoint(opts.Checkpoint, target, digest, total); err != nil {
+			return "", err
+		}
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunks := make(chan uint64)
+	done := make(chan uint64)
+	found := make(chan string, 1)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		for start := next; start < total; start += chunkSize {
+			select {
+			case chunks <- start:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			deriver := bip39.NewSeedDeriver(mnemonic)
+			defer deriver.Wipe()
+			for start := range chunks {
+				for i := start; i < chunkEnd(start, total); i++ {
+					candidate := space.Candidate(i)
+					seed, err := deriver.SeedContext(ctx, candidate)
+					if err != nil {
+						return
+					}
+
+					matched, err := target.Match(seed)
+					wipe(seed)
+					if err != nil || matched {
+						if err != nil {
+							select {
+							case errs <- err:
+							default:
+							}
+						} else {
+							select {
+							case found <- candidate:
+							default:
+							}
+						}
+						cancel()
+						return
+					}
+				}
+
+				select {
+				case done <- start:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	began := time.Now()
+	tried := next
+	resumed := next
+	finished := map[uint64]bool{}
+	report := func() error {
+		if opts.Progress != nil {
+			p := Progress{Tried: tried, Total: total, Elapsed: time.Since(began)}
+			if p.Elapsed > 0 {
+				p.Rate = float64(tried-resumed) / p.Elapsed.Seconds()
+			}
+			opts.Progress(p)
+		}
+		if opts.Checkpoint != "" {
+			return writeCheckpoint(opts.Checkpoint, checkpoint{Target: target.String(), Space: digest, Total: total, Next: next})
+		}
+
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for running := true; running; {
+		select {
+		case start, ok := <-done:
+			if !ok {
+				running = false
+				break
+			}
+
+			// Chunks finish out of order, and the checkpoint only moves past
+			// a chunk once all the chunks before it are finished.
+			tried += chunkEnd(start, total) - start
+			finished[start] = true
+			for finished[next] {
+				delete(finished, next)
+				next = chunkEnd(next, total)
+			}
+
+		case <-ticker.C:
+			if err := report(); err != nil {
+				cancel()
+				return "", err
+			}
+		}
+	}
+
+	err = report()
+	select {
+	case passphrase := <-found:
+		return passphrase, nil
+	case err := <-errs:
+		return "", err
+	default:
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := parent.Err(); err != nil {
+		return "", err
+	}
+
+	return "", ErrNotFound
+}
+
+// chunkEnd returns the index after the chunk starting at start.
+func chunkEnd(start uint64, total uint64) uint64 {
+	if total-start < chunkSize {
+		return total
+	}
+
+	return start + chunkSize
+}
+
+// spaceDigest returns the digest identifying a search space in a checkpoint.
+// It is keyed with the entropy of the mnemonic, so that it does not reveal
+// the candidates, such as a remembered guess, without the mnemonic.
+func spaceDigest(lang string, entropy []byte, space Space) string {
+	mac := hmac.New(sha256.New, entropy)
+	writeString(mac, lang)
+	writeSpace(mac, space)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// readCheckpoint returns the position saved in a checkpoint file, or 0 if the
+// file does not exist.
+func readCheckpoint(path string, target Target, digest string, total uint64) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var c checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return 0, err
+	}
+	if c.Target != target.String() || c.Space != digest || c.Total != total || c.Next > total {
+		return 0, ErrCheckpointMismatch
+	}
+
+	return c.Next, nil
+}
+
+// writeCheckpoint replaces a checkpoint file, through a temporary file so that
+// an interrupted write never leaves a corrupted checkpoint.
+func writeCheckpoint(path string, c checkpoint) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,270 @@
+package recovery
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/address"
+	"github.com/decen-one/go-bip39/assert"
+	"github.com/decen-one/go-bip39/bip32"
+)
+
+const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func newTarget(t *testing.T, passphrase string) Target {
+	fingerprint, err := bip39.Fingerprint("english", mnemonic, passphrase)
+	assert.Nil(t, err)
+
+	target, err := NewFingerprintTarget(hex.EncodeToString(fingerprint))
+	assert.Nil(t, err)
+
+	return target
+}
+
+func TestMask(t *testing.T) {
+	for _, test := range []struct {
+		pattern   string
+		len       uint64
+		index     uint64
+		candidate string
+	}{
+		{"a?d", 10, 3, "a3"},
+		{"?l?u", 676, 27, "bB"},
+		{"??x", 1, 0, "?x"},
+		{"ü?s", 33, 0, "ü "},
+		{"", 1, 0, ""},
+	} {
+		m, err := NewMask(test.pattern)
+		assert.Nil(t, err)
+		assert.Equal(t, test.len, m.Len())
+		assert.EqualString(t, test.candidate, m.Candidate(test.index))
+	}
+
+	for _, pattern := range []string{"?", "abc?", "?x", "?ü"} {
+		_, err := NewMask(pattern)
+		assert.Equal(t, ErrInvalidMask, err)
+	}
+
+	_, err := NewMask(strings.Repeat("?a", 9))
+	assert.Nil(t, err)
+	_, err = NewMask(strings.Repeat("?a", 10))
+	assert.Equal(t, ErrSpaceTooLarge, err)
+}
+
+func TestReadList(t *testing.T) {
+	list, err := ReadList(strings.NewReader("first\r\n\nsecond\nthird"))
+	assert.Nil(t, err)
+	assert.EqualStringsSlices(t, []string{"first", "second", "third"}, list)
+
+	mask, _ := NewMask("?d")
+	space, err := Join(list, mask)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(13), space.Len())
+	assert.EqualString(t, "third", space.Candidate(2))
+	assert.EqualString(t, "9", space.Candidate(12))
+
+	huge, _ := NewMask(strings.Repeat("?a", 9) + "?d")
+	_, err = Join(huge, huge, huge)
+	assert.Equal(t, ErrSpaceTooLarge, err)
+}
+
+func TestTypos(t *testing.T) {
+	assert.EqualStringsSlices(t, []string{"abc"}, mustTypos(t, "abc", 0))
+
+	one := mustTypos(t, "Pass", 1)
+	assert.EqualString(t, "Pass", one[0])
+	seen := map[string]bool{}
+	for _, typo := range one {
+		assert.False(t, seen[typo])
+		seen[typo] = true
+	}
+	for _, typo := range []string{"pass", "PASS", "Pas", "ass", "Psas", "PPass", "Pads", "Paas", "Pqass", "Paqss"} {
+		assert.True(t, seen[typo])
+	}
+	assert.False(t, seen["pas"])
+
+	two := mustTypos(t, "Pass", 2)
+	assert.EqualStringsSlices(t, one, two[:len(one)])
+	assert.True(t, len(two) > len(one))
+	for _, typo := range []string{"pas", "Pad", "Pa"} {
+		found := false
+		for _, candidate := range two {
+			found = found || candidate == typo
+		}
+		assert.True(t, found)
+	}
+
+	_, err := Typos("Satoshi2009", 3)
+	assert.Equal(t, ErrSpaceTooLarge, err)
+}
+
+func TestTargets(t *testing.T) {
+	seed := bip39.NewSeed(mnemonic, "")
+	other := bip39.NewSeed(mnemonic, "other")
+
+	target, err := NewFingerprintTarget("73c5da0a")
+	assert.Nil(t, err)
+	assert.EqualString(t, "fingerprint 73c5da0a", target.String())
+
+	for _, fingerprint := range []string{"", "73c5da", "73c5da0z"} {
+		_, err := NewFingerprintTarget(fingerprint)
+		assert.Equal(t, bip39.ErrInvalidFingerprint, err)
+	}
+
+	// The first BIP84 address of the test vectors of BIP84.
+	addressTarget, err := NewAddressTarget("bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", address.P2WPKH, address.Mainnet)
+	assert.Nil(t, err)
+	_, err = NewAddressTarget("bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", address.Type(42), address.Mainnet)
+	assert.Equal(t, address.ErrInvalidType, err)
+
+	master, err := bip32.NewMasterKey(seed)
+	assert.Nil(t, err)
+	account, err := master.Derive("m/84'/0'/0'")
+	assert.Nil(t, err)
+
+	keyTarget, err := NewExtendedKeyTarget(account.PublicKey().String(), "m/84'/0'/0'")
+	assert.Nil(t, err)
+	privateKeyTarget, err := NewExtendedKeyTarget(account.String(), "m/84'/0'/0'")
+	assert.Nil(t, err)
+	assert.EqualString(t, keyTarget.String(), privateKeyTarget.String())
+
+	_, err = NewExtendedKeyTarget(account.PublicKey().String(), "m/84'/0'")
+	assert.Equal(t, ErrInvalidPath, err)
+
+	for _, target := range []Target{target, addressTarget, keyTarget, privateKeyTarget} {
+		matched, err := target.Match(seed)
+		assert.Nil(t, err)
+		assert.True(t, matched)
+
+		matched, err = target.Match(other)
+		assert.Nil(t, err)
+		assert.False(t, matched)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	mask, _ := NewMask("Satoshi?d")
+	target := newTarget(t, "Satoshi7")
+
+	var reports []Progress
+	for _, workers := range []int{0, 1, 3} {
+		passphrase, err := Search(context.Background(), "english", mnemonic, target, mask, Options{
+			Workers:  workers,
+			Progress: func(p Progress) { reports = append(reports, p) },
+		})
+		assert.Nil(t, err)
+		assert.EqualString(t, "Satoshi7", passphrase)
+	}
+	assert.True(t, len(reports) >= 3)
+	assert.Equal(t, uint64(10), reports[0].Total)
+
+	passphrase, err := Search(context.Background(), "english", mnemonic, newTarget(t, "TREZOR"), mustTypos(t, "trezpr", 2), Options{})
+	assert.Nil(t, err)
+	assert.EqualString(t, "TREZOR", passphrase)
+
+	reports = nil
+	digits, _ := NewMask("?d?d")
+	_, err = Search(context.Background(), "english", mnemonic, target, digits, Options{
+		Progress: func(p Progress) { reports = append(reports, p) },
+	})
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, uint64(100), reports[len(reports)-1].Tried)
+
+	_, err = Search(context.Background(), "english", "abandon abandon", target, digits, Options{})
+	assert.NotNil(t, err)
+}
+
+func readCheckpointFile(t *testing.T, path string) checkpoint {
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+
+	var c checkpoint
+	assert.Nil(t, json.Unmarshal(data, &c))
+
+	return c
+}
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	mask, _ := NewMask("?d?d?d")
+	target := newTarget(t, "042")
+
+	// A cancelled search saves where it stopped.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Search(ctx, "english", mnemonic, target, mask, Options{Checkpoint: path})
+	assert.Equal(t, context.Canceled, err)
+	c := readCheckpointFile(t, path)
+	assert.EqualString(t, target.String(), c.Target)
+	assert.Equal(t, 64, len(c.Space))
+	assert.Equal(t, uint64(1000), c.Total)
+	assert.Equal(t, uint64(0), c.Next)
+
+	passphrase, err := Search(context.Background(), "english", mnemonic, target, mask, Options{Checkpoint: path})
+	assert.Nil(t, err)
+	assert.EqualString(t, "042", passphrase)
+
+	// Resuming past the passphrase skips it, and an exhausted search saves
+	// the end of the search space.
+	c.Next = 936
+	data, _ := json.Marshal(c)
+	assert.Nil(t, os.WriteFile(path, data, 0o600))
+
+	var first Progress
+	_, err = Search(context.Background(), "english", mnemonic, target, mask, Options{
+		Checkpoint: path,
+		Progress: func(p Progress) {
+			if first.Total == 0 {
+				first = p
+			}
+		},
+	})
+	assert.Equal(t, ErrNotFound, err)
+	assert.True(t, first.Tried >= 936)
+	assert.Equal(t, uint64(1000), readCheckpointFile(t, path).Next)
+
+	for _, search := range []struct {
+		target Target
+		space  Space
+	}{
+		{newTarget(t, "043"), mask},
+		{target, mustTypos(t, "042", 1)},
+	} {
+		_, err = Search(context.Background(), "english", mnemonic, search.target, search.space, Options{Checkpoint: path})
+		assert.Equal(t, ErrCheckpointMismatch, err)
+	}
+
+	// Another search space of the same length does not resume from the
+	// checkpoint, which would skip its candidates before Next.
+	c = readCheckpointFile(t, path)
+	c.Next = 500
+	data, _ = json.Marshal(c)
+	prefixed, _ := NewMask("x?d?d?d")
+	reordered := make(List, mask.Len())
+	for i := range reordered {
+		reordered[i] = mask.Candidate(mask.Len() - 1 - uint64(i))
+	}
+	for _, space := range []Space{prefixed, reordered} {
+		assert.Equal(t, mask.Len(), space.Len())
+		assert.Nil(t, os.WriteFile(path, data, 0o600))
+		_, err = Search(context.Background(), "english", mnemonic, target, space, Options{Checkpoint: path})
+		assert.Equal(t, ErrCheckpointMismatch, err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+}
+
+func mustTypos(t *testing.T, guess string, mistakes int) List {
+	typos, err := Typos(guess, mistakes)
+	assert.Nil(t, err)
+
+	return typos
+}
@@ -0,0 +1,335 @@
+package recovery
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidMask is returned when a mask has an unknown or incomplete
+	// placeholder.
+	ErrInvalidMask = errors.New("Invalid mask")
+
+	// ErrSpaceTooLarge is returned when a search space has more than 2^64-1
+	// candidates, or when Typos would list more than MaxTypos candidates.
+	ErrSpaceTooLarge = errors.New("Search space too large")
+)
+
+// MaxTypos is the largest number of candidates Typos lists. Unlike a mask,
+// the typos of a guess are built in memory to remove duplicates, and each
+// mistake multiplies their number by about 20 times the guess length.
+const MaxTypos = 1 << 20
+
+// Space is an indexed search space of candidate passphrases. Indexing lets a
+// search be split between workers and resumed from a checkpoint.
+type Space interface {
+	// Len returns the number of candidates.
+	Len() uint64
+
+	// Candidate returns the candidate at index i, for i < Len().
+	Candidate(i uint64) string
+}
+
+// List is a search space of the passphrases in a list.
+type List []string
+
+// ReadList reads a list of passphrases with one passphrase per line, such as
+// a wordlist file. Empty lines are skipped.
+func ReadList(r io.Reader) (List, error) {
+	var list List
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSuffix(scanner.Text(), "\r"); line != "" {
+			list = append(list, line)
+		}
+	}
+
+	return list, scanner.Err()
+}
+
+// Len returns the number of passphrases in the list.
+func (l List) Len() uint64 {
+	return uint64(len(l))
+}
+
+// Candidate returns the passphrase at index i.
+func (l List) Candidate(i uint64) string {
+	return l[i]
+}
+
+// Character sets of the mask placeholders.
+const (
+	lowerChars   = "abcdefghijklmnopqrstuvwxyz"
+	upperChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars   = "0123456789"
+	specialChars = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+var maskCharsets = map[byte]string{
+	'l': lowerChars,
+	'u': upperChars,
+	'd': digitChars,
+	's': specialChars,
+	'a': lowerChars + upperChars + digitChars + specialChars,
+	'?': "?",
+}
+
+// Mask is a search space of the passphrases matching a mask such as
+// "Satoshi?d?d?s". A mask uses the placeholders of hashcat:
+//
+//	?l  lowercase letter
+//	?u  uppercase letter
+//	?d  digit
+//	?s  ASCII special character, including space
+//	?a  any of the above
+//	??  literal '?'
+//
+// Any other character stands for itself.
+type Mask struct {
+	positions [][]rune
+	len       uint64
+}
+
+// NewMask parses a mask.
+func NewMask(pattern string) (*Mask, error) {
+	m := &Mask{len: 1}
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		charset := string(runes[i])
+		if runes[i] == '?' {
+			i++
+			if i == len(runes) || runes[i] > unicode.MaxASCII {
+				return nil, ErrInvalidMask
+			}
+
+			var ok bool
+			if charset, ok = maskCharsets[byte(runes[i])]; !ok {
+				return nil, ErrInvalidMask
+			}
+		}
+
+		position := []rune(charset)
+		if m.len > math.MaxUint64/uint64(len(position)) {
+			return nil, ErrSpaceTooLarge
+		}
+		m.len *= uint64(len(position))
+		m.positions = append(m.positions, position)
+	}
+
+	return m, nil
+}
+
+// Len returns the number of passphrases matching the mask.
+func (m *Mask) Len() uint64 {
+	return m.len
+}
+
+// Candidate returns the passphrase at index i. The last position of the mask
+// changes fastest.
+func (m *Mask) Candidate(i uint64) string {
+	candidate := make([]rune, len(m.positions))
+	for j := len(m.positions) - 1; j >= 0; j-- {
+		position := m.positions[j]
+		candidate[j] = position[i%uint64(len(position))]
+		i /= uint64(len(position))
+	}
+
+	return string(candidate)
+}
+
+type joined []Space
+
+// Join returns the search space of the candidates of each space in turn.
+func Join(spaces ...Space) (Space, error) {
+	var total uint64
+	for _, space := range spaces {
+		if total > math.MaxUint64-space.Len() {
+			return nil, ErrSpaceTooLarge
+		}
+		total += space.Len()
+	}
+
+	return joined(spaces), nil
+}
+
+func (j joined) Len() uint64 {
+	var total uint64
+	for _, space := range j {
+		total += space.Len()
+	}
+
+	return total
+}
+
+func (j joined) Candidate(i uint64) string {
+	for _, space := range j {
+		if i < space.Len() {
+			return space.Candidate(i)
+		}
+		i -= space.Len()
+	}
+
+	panic("recovery: candidate index out of range")
+}
+
+// writeSpace writes the definition of a search space to h: the passphrases
+// of a list, the character sets of a mask or the spaces joined. A space of
+// another type is written as all its candidates.
+func writeSpace(h hash.Hash, space Space) {
+	switch s := space.(type) {
+	case List:
+		writeString(h, "list")
+		writeUint(h, uint64(len(s)))
+		for _, candidate := range s {
+			writeString(h, candidate)
+		}
+
+	case *Mask:
+		writeString(h, "mask")
+		writeUint(h, uint64(len(s.positions)))
+		for _, position := range s.positions {
+			writeString(h, string(position))
+		}
+
+	case joined:
+		writeString(h, "join")
+		writeUint(h, uint64(len(s)))
+		for _, space := range s {
+			writeSpace(h, space)
+		}
+
+	default:
+		writeString(h, "space")
+		writeUint(h, space.Len())
+		for i := uint64(0); i < space.Len(); i++ {
+			writeString(h, space.Candidate(i))
+		}
+	}
+}
+
+// writeString writes s to h prefixed with its length, so that the strings
+// written in a row can not be split differently.
+func writeString(h hash.Hash, s string) {
+	writeUint(h, uint64(len(s)))
+	_, _ = h.Write([]byte(s))
+}
+
+func writeUint(h hash.Hash, v uint64) {
+	_, _ = h.Write(binary.BigEndian.AppendUint64(nil, v))
+}
+
+// keyboardRows are the rows of a US QWERTY keyboard, used to find the keys
+// next to a mistyped one.
+var keyboardRows = []string{
+	"1234567890-=",
+	"qwertyuiop[]",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+}
+
+// keyboardNeighbours maps each lowercase key to the keys around it.
+var keyboardNeighbours = func() map[rune][]rune {
+	neighbours := map[rune][]rune{}
+	for r, row := range keyboardRows {
+		for c, key := range row {
+			// Each row is shifted right of the one above, so the keys above
+			// are at c and c+1 and the keys below at c-1 and c.
+			for _, offset := range [][2]int{{0, -1}, {0, 1}, {-1, 0}, {-1, 1}, {1, -1}, {1, 0}} {
+				nr, nc := r+offset[0], c+offset[1]
+				if nr >= 0 && nr < len(keyboardRows) && nc >= 0 && nc < len(keyboardRows[nr]) {
+					neighbours[key] = append(neighbours[key], rune(keyboardRows[nr][nc]))
+				}
+			}
+		}
+	}
+
+	return neighbours
+}()
+
+// neighbours returns the keys around r, in the case of r.
+func neighbours(r rune) []rune {
+	keys := keyboardNeighbours[unicode.ToLower(r)]
+	if !unicode.IsUpper(r) {
+		return keys
+	}
+
+	upper := make([]rune, len(keys))
+	for i, key := range keys {
+		upper[i] = unicode.ToUpper(key)
+	}
+
+	return upper
+}
+
+// Typos returns the search space of the passphrases within the given number
+// of typing mistakes of a remembered guess, starting with the guess itself
+// and the fewest mistakes. A mistake is a missing, doubled or swapped
+// character, a character with the wrong case, a key next to the intended one
+// typed instead of it or pressed together with it, or the whole guess typed
+// in lowercase or uppercase.
+//
+// The candidates are built as Typos is called, so it returns
+// ErrSpaceTooLarge as soon as there would be more than MaxTypos of them,
+// which happens with 3 mistakes of a guess of about 10 characters, or 2
+// mistakes of one of about 80.
+func Typos(guess string, mistakes int) (List, error) {
+	list := List{guess}
+	seen := map[string]bool{guess: true}
+
+	level := list
+	for n := 0; n < mistakes; n++ {
+		var next List
+		for _, candidate := range level {
+			for _, typo := range typos(candidate) {
+				if !seen[typo] {
+					if len(list)+len(next) == MaxTypos {
+						return nil, ErrSpaceTooLarge
+					}
+					seen[typo] = true
+					next = append(next, typo)
+				}
+			}
+		}
+
+		list = append(list, next...)
+		level = next
+	}
+
+	return list, nil
+}
+
+// typos returns the candidates one mistake away from s.
+func typos(s string) []string {
+	runes := []rune(s)
+	edit := func(i, j int, replacement ...rune) string {
+		return string(runes[:i]) + string(replacement) + string(runes[j:])
+	}
+
+	result := []string{strings.ToLower(s), strings.ToUpper(s)}
+
+	for i, r := range runes {
+		result = append(result, edit(i, i+1), edit(i, i+1, r, r))
+		if i+1 < len(runes) {
+			result = append(result, edit(i, i+2, runes[i+1], r))
+		}
+		if unicode.IsUpper(r) {
+			result = append(result, edit(i, i+1, unicode.ToLower(r)))
+		} else if unicode.IsLower(r) {
+			result = append(result, edit(i, i+1, unicode.ToUpper(r)))
+		}
+
+		for _, key := range neighbours(r) {
+			result = append(result, edit(i, i+1, key), edit(i, i+1, key, r), edit(i, i+1, r, key))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,137 @@
+package recovery
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/address"
+	"github.com/decen-one/go-bip39/bip32"
+	"github.com/decen-one/go-bip39/slip10"
+)
+
+// ErrInvalidPath is returned when the derivation path of an extended key
+// target does not have the depth of the key.
+var ErrInvalidPath = errors.New("Derivation path does not match the depth of the extended key")
+
+// Target recognizes the seed of the passphrase being searched for.
+type Target interface {
+	// Match reports whether the seed derives the target.
+	Match(seed []byte) (bool, error)
+
+	// String describes the target. It is stored in checkpoints to tell
+	// searches apart, so it must not contain secrets.
+	String() string
+}
+
+type fingerprintTarget []byte
+
+// NewFingerprintTarget returns a target matching the master key fingerprint
+// of a seed, given as 4 hex encoded bytes such as "73c5da0a".
+func NewFingerprintTarget(fingerprint string) (Target, error) {
+	expected, err := hex.DecodeString(fingerprint)
+	if err != nil || len(expected) != 4 {
+		return nil, bip39.ErrInvalidFingerprint
+	}
+
+	return fingerprintTarget(expected), nil
+}
+
+func (t fingerprintTarget) Match(seed []byte) (bool, error) {
+	master, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(t, bip32.Hash160(master.PublicKeyBytes())[:4]), nil
+}
+
+func (t fingerprintTarget) String() string {
+	return "fingerprint " + hex.EncodeToString(t)
+}
+
+type keyTarget struct {
+	key  *bip32.Key
+	path string
+}
+
+// NewExtendedKeyTarget returns a target matching the extended public key
+// derived at a path, such as an account xpub at "m/84'/0'/0'". Extended
+// private keys are accepted too, and only their public key is compared.
+func NewExtendedKeyTarget(key string, path string) (Target, error) {
+	k, err := bip32.B58Deserialize(key)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := slip10.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(indexes) != int(k.Depth) {
+		return nil, ErrInvalidPath
+	}
+
+	return &keyTarget{key: k.PublicKey(), path: path}, nil
+}
+
+func (t *keyTarget) Match(seed []byte) (bool, error) {
+	master, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return false, err
+	}
+
+	// The chain code only depends on private derivation, so it rules out
+	// nearly all wrong seeds before computing a public key.
+	key, err := master.Derive(t.path)
+	if err != nil || !bytes.Equal(t.key.ChainCode, key.ChainCode) {
+		return false, err
+	}
+
+	return bytes.Equal(t.key.Key, key.PublicKeyBytes()), nil
+}
+
+func (t *keyTarget) String() string {
+	return fmt.Sprintf("key %s at %s", t.key, t.path)
+}
+
+type addressTarget struct {
+	address string
+	t       address.Type
+	net     *address.Network
+}
+
+// NewAddressTarget returns a target matching the first receive address of
+// the given type, at account 0 and index 0, on the given network.
+func NewAddressTarget(addr string, t address.Type, net *address.Network) (Target, error) {
+	if t.Purpose() == 0 {
+		return nil, address.ErrInvalidType
+	}
+
+	return &addressTarget{address: addr, t: t, net: net}, nil
+}
+
+func (t *addressTarget) Match(seed []byte) (bool, error) {
+	master, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return false, err
+	}
+
+	key, err := master.Derive(address.Path(t.t, t.net, 0, false, 0))
+	if err != nil {
+		return false, err
+	}
+
+	addr, err := address.Encode(t.t, t.net, key.PublicKeyBytes())
+	if err != nil {
+		return false, err
+	}
+
+	return addr == t.address, nil
+}
+
+func (t *addressTarget) String() string {
+	return fmt.Sprintf("address %s", t.address)
+}
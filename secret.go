@@ -0,0 +1,111 @@
+package bip39
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+)
+
+// ErrMemoryLockUnsupported is returned when enabling memory locking on a
+// platform other than Linux.
+var ErrMemoryLockUnsupported = errors.New("Memory locking is not supported on this platform")
+
+// lockMemory is 1 when new secrets are locked into memory.
+var lockMemory int32
+
+// Secret is a buffer of secret data, such as entropy, a mnemonic or a seed,
+// that is wiped as soon as it is no longer needed.
+//
+// Wiping is best effort: the Go runtime may have copied the data, for
+// instance when growing a stack, and strings holding secrets can never be
+// wiped. The functions returning a Secret only work on byte slices and wipe
+// their intermediate buffers to keep the number of copies to a minimum.
+type Secret struct {
+	data []byte
+
+	// locked is the whole pages locked into memory for data, or nil.
+	locked []byte
+}
+
+// SetMemoryLocking sets whether the secrets allocated afterwards are locked
+// into memory with mlock, which keeps them out of swap. A secret that can not
+// be locked, for instance because of RLIMIT_MEMLOCK, is allocated unlocked.
+// ErrMemoryLockUnsupported is returned on platforms other than Linux.
+func SetMemoryLocking(enabled bool) error {
+	if !enabled {
+		atomic.StoreInt32(&lockMemory, 0)
+		return nil
+	}
+	if !memoryLockSupported {
+		return ErrMemoryLockUnsupported
+	}
+
+	atomic.StoreInt32(&lockMemory, 1)
+	return nil
+}
+
+// NewSecret returns a zeroed secret of the given size.
+func NewSecret(size int) *Secret {
+	if atomic.LoadInt32(&lockMemory) == 1 {
+		if s := newLockedSecret(size); s != nil {
+			return s
+		}
+	}
+
+	return &Secret{data: make([]byte, size)}
+}
+
+// NewSecretFromBytes returns a secret holding a copy of b. The caller should
+// wipe b afterwards.
+func NewSecretFromBytes(b []byte) *Secret {
+	s := NewSecret(len(b))
+	copy(s.data, b)
+
+	return s
+}
+
+// Bytes returns the data of the secret, which is only valid until the secret
+// is wiped. A locked secret is wiped when it is garbage collected, so it must
+// be kept reachable while its data is used.
+func (s *Secret) Bytes() []byte {
+	return s.data
+}
+
+// Len returns the size of the secret, 0 once it is wiped.
+func (s *Secret) Len() int {
+	return len(s.data)
+}
+
+// Locked reports whether the secret is locked into memory.
+func (s *Secret) Locked() bool {
+	return s.locked != nil
+}
+
+// Wipe zeroes the secret and unlocks its memory. The secret is empty
+// afterwards.
+func (s *Secret) Wipe() {
+	wipe(s.data)
+	if s.locked != nil {
+		unlockMemory(s.locked)
+		runtime.SetFinalizer(s, nil)
+	}
+
+	s.data = nil
+	s.locked = nil
+}
+
+// wipe zeroes b.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+	runtime.KeepAlive(b)
+}
+
+// wipeIndexes zeroes word indexes.
+func wipeIndexes(indexes []int) {
+	for i := range indexes {
+		indexes[i] = 0
+	}
+	runtime.KeepAlive(indexes)
+}
@@ -0,0 +1,40 @@
+package bip39
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const memoryLockSupported = true
+
+// newLockedSecret returns a secret on whole pages of its own locked into
+// memory, so that unlocking it never unlocks another secret, or nil if the
+// pages can not be locked. A secret that is never wiped is wiped when it is
+// garbage collected, as the pages would otherwise stay locked.
+func newLockedSecret(size int) *Secret {
+	pageSize := os.Getpagesize()
+	pages := (size + pageSize - 1) / pageSize
+	if pages == 0 {
+		pages = 1
+	}
+
+	// The Go heap does not move objects, so the pages stay locked for the
+	// life of the buffer.
+	buf := make([]byte, (pages+1)*pageSize)
+	offset := (pageSize - int(uintptr(unsafe.Pointer(&buf[0]))%uintptr(pageSize))) % pageSize
+	locked := buf[offset : offset+pages*pageSize]
+	if err := syscall.Mlock(locked); err != nil {
+		return nil
+	}
+
+	s := &Secret{data: locked[:size:size], locked: locked}
+	runtime.SetFinalizer(s, (*Secret).Wipe)
+
+	return s
+}
+
+func unlockMemory(b []byte) {
+	_ = syscall.Munlock(b) // The pages are locked, so this can not fail
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package bip39
+
+const memoryLockSupported = false
+
+func newLockedSecret(size int) *Secret {
+	return nil
+}
+
+func unlockMemory(b []byte) {}
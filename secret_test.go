@@ -0,0 +1,168 @@
+package bip39
+
+import (
+	"crypto/sha512"
+	"encoding"
+	"encoding/hex"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/decen-one/go-bip39/assert"
+)
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestSecret(t *testing.T) {
+	s := NewSecret(32)
+	assert.Equal(t, 32, s.Len())
+	assert.True(t, isZero(s.Bytes()))
+
+	data := s.Bytes()
+	for i := range data {
+		data[i] = byte(i + 1)
+	}
+	s.Wipe()
+	assert.True(t, isZero(data))
+	assert.Equal(t, 0, s.Len())
+	assert.False(t, s.Locked())
+
+	original := []byte{1, 2, 3}
+	s = NewSecretFromBytes(original)
+	assert.EqualByteSlices(t, original, s.Bytes())
+	s.Wipe()
+	assert.EqualByteSlices(t, []byte{1, 2, 3}, original)
+}
+
+func TestMemoryLocking(t *testing.T) {
+	err := SetMemoryLocking(true)
+	if runtime.GOOS != "linux" {
+		assert.Equal(t, ErrMemoryLockUnsupported, err)
+		return
+	}
+	assert.Nil(t, err)
+	defer func() { _ = SetMemoryLocking(false) }()
+
+	// Secrets that can not be locked, for instance because RLIMIT_MEMLOCK is
+	// too low, are silently allocated unlocked.
+	probe := NewSecret(5000)
+	locked := probe.Locked()
+	probe.Wipe()
+	if !locked {
+		t.Skip("memory locking is unavailable")
+	}
+
+	for _, size := range []int{0, 1, 64, 5000} {
+		s := NewSecret(size)
+		assert.True(t, s.Locked())
+		assert.Equal(t, size, s.Len())
+		assert.True(t, isZero(s.Bytes()))
+
+		data := s.Bytes()
+		for i := range data {
+			data[i] = 0xff
+		}
+		s.Wipe()
+		assert.True(t, isZero(data))
+		assert.False(t, s.Locked())
+	}
+
+	// A locked secret that is not wiped is wiped once unreachable.
+	data := NewSecret(64).Bytes()
+	for i := range data {
+		data[i] = 0xff
+	}
+	for i := 0; i < 100 && !isZero(data); i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(t, isZero(data))
+
+	assert.Nil(t, SetMemoryLocking(false))
+	assert.False(t, NewSecret(64).Locked())
+}
+
+func TestSecretFunctions(t *testing.T) {
+	for _, vector := range testVectors() {
+		entropy, err := hex.DecodeString(vector.entropy)
+		assert.Nil(t, err)
+
+		mnemonic, err := NewMnemonicSecret(vector.lang, entropy)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.mnemonic, string(mnemonic.Bytes()))
+
+		decoded, err := EntropyFromMnemonicSecret(vector.lang, mnemonic.Bytes())
+		assert.Nil(t, err)
+		assert.EqualByteSlices(t, entropy, decoded.Bytes())
+
+		seed := NewSeedSecret(mnemonic.Bytes(), []byte(vector.password))
+		assert.EqualString(t, vector.seed, hex.EncodeToString(seed.Bytes()))
+
+		mnemonic.Wipe()
+		decoded.Wipe()
+		seed.Wipe()
+	}
+
+	_, err := NewMnemonicSecret("english", make([]byte, 15))
+	assert.Equal(t, ErrEntropyLengthInvalid, err)
+	_, err = NewMnemonicSecret("klingon", make([]byte, 16))
+	assert.Equal(t, ErrInvalidLanguage, err)
+
+	for mnemonic, expected := range map[string]error{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon":         ErrInvalidMnemonic,
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon zzzzzzz": ErrInvalidMnemonic,
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon": ErrChecksumIncorrect,
+	} {
+		_, err := EntropyFromMnemonicSecret("english", []byte(mnemonic))
+		assert.Equal(t, expected, err)
+	}
+	_, err = EntropyFromMnemonicSecret("klingon", []byte(testVectors()[0].mnemonic))
+	assert.Equal(t, ErrInvalidLanguage, err)
+}
+
+func TestSeedDeriverWipe(t *testing.T) {
+	deriver := NewSeedDeriver(testVectors()[0].mnemonic)
+	deriver.Seed("TREZOR")
+
+	// The buffers holding the password and the PBKDF2 blocks are wiped after
+	// each seed.
+	assert.True(t, isZero(deriver.salt[:cap(deriver.salt)]))
+	assert.True(t, isZero(deriver.u[:cap(deriver.u)]))
+
+	innerState, outerState := deriver.prf.innerState, deriver.prf.outerState
+	deriver.Wipe()
+	assert.True(t, isZero(innerState))
+	assert.True(t, isZero(outerState))
+
+	// The digests are back to their initial state.
+	initial, _ := sha512.New().(encoding.BinaryMarshaler).MarshalBinary()
+	for _, digest := range []interface{}{deriver.prf.inner, deriver.prf.outer} {
+		state, _ := digest.(encoding.BinaryMarshaler).MarshalBinary()
+		assert.EqualByteSlices(t, initial, state)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	for _, vector := range testVectors() {
+		entropy, _ := hex.DecodeString(vector.entropy)
+
+		var indexes [maxWords]int
+		words := indexes[:len(entropy)*3/4]
+		encodeIndexes(entropy, words)
+
+		decoded := make([]byte, len(entropy))
+		assert.True(t, decodeIndexes(words, decoded))
+		assert.EqualByteSlices(t, entropy, decoded)
+
+		words[len(words)-1] ^= 1
+		assert.False(t, decodeIndexes(words, decoded))
+	}
+}
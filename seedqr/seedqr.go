@@ -0,0 +1,235 @@
+// Package seedqr encodes mnemonics as SeedSigner's SeedQR formats and renders
+// them as QR codes.
+//
+// Standard SeedQR is the concatenation of the 4 digit, zero padded, wordlist
+// indexes of the words, encoded in numeric mode. Compact SeedQR is the raw
+// entropy encoded in byte mode. Both use error correction level L and the
+// versions of the specification:
+//
+//	           12 words       24 words
+//	Standard   2 (25x25)      3 (29x29)
+//	Compact    1 (21x21)      2 (25x25)
+package seedqr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/decen-one/go-bip39"
+	"rsc.io/qr"
+	"rsc.io/qr/coding"
+)
+
+// quietZone is the width in modules of the white border around a QR code.
+const quietZone = 4
+
+var (
+	// ErrInvalidMnemonicSize is returned when trying to encode a mnemonic that
+	// does not have 12 or 24 words.
+	ErrInvalidMnemonicSize = errors.New("SeedQR supports only 12 and 24 word mnemonics")
+
+	// ErrInvalidSeedQR is returned when decoding data that is neither a
+	// Standard nor a Compact SeedQR.
+	ErrInvalidSeedQR = errors.New("Invalid SeedQR data")
+)
+
+// versions maps the number of words to the QR versions of the Standard and
+// Compact formats.
+var versions = map[int][2]coding.Version{
+	12: {2, 1},
+	24: {3, 2},
+}
+
+// EncodeStandard returns the Standard SeedQR digits of a mnemonic.
+func EncodeStandard(lang string, mnemonic string) (string, error) {
+	words, err := validWords(lang, mnemonic)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, word := range words {
+		index, err := bip39.GetWordIndex(lang, word)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "%04d", index)
+	}
+
+	return sb.String(), nil
+}
+
+// DecodeStandard returns the mnemonic of Standard SeedQR digits.
+func DecodeStandard(lang string, digits string) (string, error) {
+	if _, ok := versions[len(digits)/4]; !ok || len(digits)%4 != 0 {
+		return "", ErrInvalidSeedQR
+	}
+
+	wordList, err := bip39.GetWordList(lang)
+	if err != nil {
+		return "", err
+	}
+
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return "", ErrInvalidSeedQR
+		}
+	}
+
+	words := make([]string, len(digits)/4)
+	for i := range words {
+		index, _ := strconv.Atoi(digits[4*i : 4*i+4]) // err is always nil
+		if index >= len(wordList) {
+			return "", ErrInvalidSeedQR
+		}
+		words[i] = wordList[index]
+	}
+
+	mnemonic := strings.Join(words, " ")
+	if _, err := bip39.EntropyFromMnemonic(lang, mnemonic); err != nil {
+		return "", err
+	}
+
+	return mnemonic, nil
+}
+
+// EncodeCompact returns the Compact SeedQR bytes of a mnemonic, its entropy.
+func EncodeCompact(lang string, mnemonic string) ([]byte, error) {
+	if _, err := validWords(lang, mnemonic); err != nil {
+		return nil, err
+	}
+
+	return bip39.EntropyFromMnemonic(lang, mnemonic)
+}
+
+// DecodeCompact returns the mnemonic of Compact SeedQR bytes.
+func DecodeCompact(lang string, data []byte) (string, error) {
+	if len(data) != 16 && len(data) != 32 {
+		return "", ErrInvalidSeedQR
+	}
+
+	return bip39.NewMnemonic(lang, data)
+}
+
+// Decode returns the mnemonic of scanned SeedQR data in either format.
+func Decode(lang string, data []byte) (string, error) {
+	switch len(data) {
+	case 16, 32:
+		return DecodeCompact(lang, data)
+	default:
+		return DecodeStandard(lang, string(data))
+	}
+}
+
+// Code is a SeedQR code.
+type Code struct {
+	// Version is the QR version, which determines the size of the code.
+	Version int
+
+	code *coding.Code
+}
+
+// NewStandardCode returns the Standard SeedQR code of a mnemonic.
+func NewStandardCode(lang string, mnemonic string) (*Code, error) {
+	digits, err := EncodeStandard(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCode(versions[len(digits)/4][0], coding.Num(digits))
+}
+
+// NewCompactCode returns the Compact SeedQR code of a mnemonic.
+func NewCompactCode(lang string, mnemonic string) (*Code, error) {
+	entropy, err := EncodeCompact(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCode(versions[len(entropy)*3/4][1], coding.String(entropy))
+}
+
+func newCode(version coding.Version, data coding.Encoding) (*Code, error) {
+	plan, err := coding.NewPlan(version, coding.L, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := plan.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Code{Version: int(version), code: code}, nil
+}
+
+// Size returns the number of modules on a side of the code, without the
+// quiet zone.
+func (c *Code) Size() int {
+	return c.code.Size
+}
+
+// Black reports whether the module at (x, y) is black.
+func (c *Code) Black(x int, y int) bool {
+	return c.code.Black(x, y)
+}
+
+// PNG renders the code with its quiet zone as a PNG image with scale pixels
+// per module.
+func (c *Code) PNG(scale int) []byte {
+	code := &qr.Code{Bitmap: c.code.Bitmap, Size: c.code.Size, Stride: c.code.Stride, Scale: scale}
+	return code.PNG()
+}
+
+// SVG renders the code with its quiet zone as an SVG image with scale units
+// per module.
+func (c *Code) SVG(scale int) string {
+	size := (c.code.Size + 2*quietZone) * scale
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, size, size)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, size, size)
+	for y := 0; y < c.code.Size; y++ {
+		for x := 0; x < c.code.Size; x++ {
+			if c.code.Black(x, y) {
+				fmt.Fprintf(&sb, "M%d %dh%dv%dh-%dz", (x+quietZone)*scale, (y+quietZone)*scale, scale, scale, scale)
+			}
+		}
+	}
+	sb.WriteString(`"/></svg>`)
+
+	return sb.String()
+}
+
+// ASCII renders the code with its quiet zone as text, two characters per
+// module, for terminals with a dark background.
+func (c *Code) ASCII() string {
+	var sb strings.Builder
+	for y := -quietZone; y < c.code.Size+quietZone; y++ {
+		for x := -quietZone; x < c.code.Size+quietZone; x++ {
+			if c.code.Black(x, y) {
+				sb.WriteString("  ")
+			} else {
+				sb.WriteString("██")
+			}
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+func validWords(lang string, mnemonic string) ([]string, error) {
+	if _, err := bip39.EntropyFromMnemonic(lang, mnemonic); err != nil {
+		return nil, err
+	}
+
+	words := strings.Fields(mnemonic)
+	if _, ok := versions[len(words)]; !ok {
+		return nil, ErrInvalidMnemonicSize
+	}
+
+	return words, nil
+}
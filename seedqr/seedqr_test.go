@@ -0,0 +1,136 @@
+package seedqr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// Test vectors from the SeedQR specification of SeedSigner.
+var testVectors = []struct {
+	mnemonic        string
+	digits          string
+	compact         string
+	standardVersion int
+	compactVersion  int
+}{
+	{
+		mnemonic:        "forum undo fragile fade shy sign arrest garment culture tube off merit",
+		digits:          "073318950739065415961602009907670428187212261116",
+		compact:         "5bbd9d71a8ec7990831aff359d426545",
+		standardVersion: 2,
+		compactVersion:  1,
+	},
+	{
+		mnemonic:        "attack pizza motion avocado network gather crop fresh patrol unusual wild holiday candy pony ranch winter theme error hybrid van cereal salon goddess expire",
+		digits:          "011513251154012711900771041507421289190620080870026613431420201617920614089619290300152408010643",
+		compact:         "0e74b64107f94cc0ccfae6a13dcbec3662154fec67e0e00999c07892597d190a",
+		standardVersion: 3,
+		compactVersion:  2,
+	},
+}
+
+func TestStandard(t *testing.T) {
+	for _, vector := range testVectors {
+		digits, err := EncodeStandard("english", vector.mnemonic)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.digits, digits)
+
+		mnemonic, err := DecodeStandard("english", digits)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.mnemonic, mnemonic)
+
+		mnemonic, err = Decode("english", []byte(digits))
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.mnemonic, mnemonic)
+	}
+
+	digits := testVectors[0].digits
+	for _, invalid := range []string{
+		"",
+		digits[:44],
+		digits[:47],
+		digits[:44] + "2048",
+		digits[:44] + "-116",
+		digits[:44] + "111a",
+	} {
+		_, err := DecodeStandard("english", invalid)
+		assert.Equal(t, ErrInvalidSeedQR, err)
+	}
+
+	// The last word does not match the checksum.
+	_, err := DecodeStandard("english", digits[:44]+"1117")
+	assert.Equal(t, bip39.ErrChecksumIncorrect, err)
+}
+
+func TestCompact(t *testing.T) {
+	for _, vector := range testVectors {
+		data, err := EncodeCompact("english", vector.mnemonic)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.compact, hex.EncodeToString(data))
+
+		mnemonic, err := DecodeCompact("english", data)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.mnemonic, mnemonic)
+
+		mnemonic, err = Decode("english", data)
+		assert.Nil(t, err)
+		assert.EqualString(t, vector.mnemonic, mnemonic)
+	}
+
+	_, err := DecodeCompact("english", make([]byte, 24))
+	assert.Equal(t, ErrInvalidSeedQR, err)
+}
+
+func TestInvalidMnemonic(t *testing.T) {
+	eighteen, err := bip39.NewRandMnemonic("english", 18)
+	assert.Nil(t, err)
+
+	_, err = EncodeStandard("english", eighteen)
+	assert.Equal(t, ErrInvalidMnemonicSize, err)
+
+	_, err = EncodeCompact("english", eighteen)
+	assert.Equal(t, ErrInvalidMnemonicSize, err)
+
+	_, err = NewStandardCode("english", eighteen)
+	assert.Equal(t, ErrInvalidMnemonicSize, err)
+
+	_, err = EncodeStandard("english", "forum undo fragile fade shy sign arrest garment culture tube off off")
+	assert.NotNil(t, err)
+}
+
+func TestCode(t *testing.T) {
+	for _, vector := range testVectors {
+		standard, err := NewStandardCode("english", vector.mnemonic)
+		assert.Nil(t, err)
+		assert.Equal(t, vector.standardVersion, standard.Version)
+		assert.Equal(t, 17+4*vector.standardVersion, standard.Size())
+
+		compact, err := NewCompactCode("english", vector.mnemonic)
+		assert.Nil(t, err)
+		assert.Equal(t, vector.compactVersion, compact.Version)
+		assert.Equal(t, 17+4*vector.compactVersion, compact.Size())
+
+		// The finder pattern corners are black and the quiet zone is white.
+		assert.True(t, standard.Black(0, 0))
+		assert.True(t, standard.Black(standard.Size()-1, 0))
+		assert.False(t, standard.Black(-1, 0))
+
+		image, err := png.Decode(bytes.NewReader(standard.PNG(4)))
+		assert.Nil(t, err)
+		assert.Equal(t, 4*(standard.Size()+2*quietZone), image.Bounds().Dx())
+
+		svg := standard.SVG(4)
+		assert.True(t, strings.HasPrefix(svg, "<svg"))
+		assert.True(t, strings.HasSuffix(svg, "</svg>"))
+
+		lines := strings.Split(strings.TrimSuffix(standard.ASCII(), "\n"), "\n")
+		assert.Equal(t, standard.Size()+2*quietZone, len(lines))
+		assert.Equal(t, 2*(standard.Size()+2*quietZone), len([]rune(lines[0])))
+	}
+}
@@ -0,0 +1,139 @@
+// Package seedxor implements Coldcard's Seed XOR: a mnemonic is split into
+// parts that are themselves valid mnemonics of the same size and whose
+// entropies XOR to the entropy of the original mnemonic. All parts are needed
+// to recover it.
+package seedxor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/decen-one/go-bip39"
+)
+
+var (
+	// ErrInvalidMnemonicSize is returned when trying to split or combine
+	// mnemonics that do not have 12, 18 or 24 words.
+	ErrInvalidMnemonicSize = errors.New("Seed XOR supports only 12, 18 and 24 word mnemonics")
+
+	// ErrInvalidPartCount is returned when trying to split a mnemonic into,
+	// or combine, fewer than 2 parts.
+	ErrInvalidPartCount = errors.New("Seed XOR requires at least 2 parts")
+
+	// ErrTooManyParts is returned when trying to split a mnemonic
+	// deterministically into more than 255 parts, as the part count and
+	// index are hashed as single bytes.
+	ErrTooManyParts = errors.New("Deterministic Seed XOR supports at most 255 parts")
+
+	// ErrMismatchedParts is returned when combining parts of different sizes.
+	ErrMismatchedParts = errors.New("All parts must have the same number of words")
+)
+
+// Split splits a mnemonic into the given number of random parts.
+func Split(lang string, mnemonic string, parts int) ([]string, error) {
+	return split(lang, mnemonic, parts, func(entropy []byte, i int) ([]byte, error) {
+		return bip39.NewEntropy(len(entropy) * 8)
+	})
+}
+
+// SplitDeterministic splits a mnemonic into the given number of parts derived
+// from seed, so that the same mnemonic, part count and seed always give the
+// same parts. Every part but the last is the truncated
+// HMAC-SHA256(seed, entropy || part count || part index), with the count and
+// index as single bytes, so there can be at most 255 parts.
+func SplitDeterministic(lang string, mnemonic string, parts int, seed []byte) ([]string, error) {
+	if parts > 255 {
+		return nil, ErrTooManyParts
+	}
+
+	return split(lang, mnemonic, parts, func(entropy []byte, i int) ([]byte, error) {
+		mac := hmac.New(sha256.New, seed)
+		_, _ = mac.Write(entropy)
+		_, _ = mac.Write([]byte{byte(parts), byte(i)})
+
+		return mac.Sum(nil)[:len(entropy)], nil
+	})
+}
+
+// Combine recovers the mnemonic from all of its parts, in any order.
+func Combine(lang string, parts []string) (string, error) {
+	if len(parts) < 2 {
+		return "", ErrInvalidPartCount
+	}
+
+	var result []byte
+	for _, part := range parts {
+		entropy, err := entropyFromMnemonic(lang, part)
+		if err != nil {
+			return "", err
+		}
+
+		if result == nil {
+			result = entropy
+			continue
+		}
+		if len(entropy) != len(result) {
+			return "", ErrMismatchedParts
+		}
+		xor(result, entropy)
+	}
+
+	return bip39.NewMnemonic(lang, result)
+}
+
+// split creates parts - 1 parts with newPart and the last part as the XOR of
+// the entropy and all other parts.
+func split(lang string, mnemonic string, parts int, newPart func(entropy []byte, i int) ([]byte, error)) ([]string, error) {
+	if parts < 2 {
+		return nil, ErrInvalidPartCount
+	}
+
+	entropy, err := entropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	last := append([]byte{}, entropy...)
+	mnemonics := make([]string, 0, parts)
+	for i := 0; i < parts-1; i++ {
+		part, err := newPart(entropy, i)
+		if err != nil {
+			return nil, err
+		}
+		xor(last, part)
+
+		partMnemonic, err := bip39.NewMnemonic(lang, part)
+		if err != nil {
+			return nil, err
+		}
+		mnemonics = append(mnemonics, partMnemonic)
+	}
+
+	lastMnemonic, err := bip39.NewMnemonic(lang, last)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(mnemonics, lastMnemonic), nil
+}
+
+func entropyFromMnemonic(lang string, mnemonic string) ([]byte, error) {
+	entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(entropy) {
+	case 16, 24, 32:
+		return entropy, nil
+	default:
+		return nil, ErrInvalidMnemonicSize
+	}
+}
+
+func xor(dst []byte, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
@@ -0,0 +1,99 @@
+package seedxor
+
+import (
+	"testing"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// Example from the Coldcard Seed XOR documentation.
+var (
+	parts = []string{
+		"romance wink lottery autumn shop bring dawn tongue range crater truth ability miss spice fitness easy legal release recall obey exchange recycle dragon room",
+		"lion misery divide hurry latin fluid camp advance illegal lab pyramid unaware eager fringe sick camera series noodle toy crowd jeans select depth lounge",
+		"vault nominee cradle silk own frown throw leg cactus recall talent worry gadget surface shy planet purpose coffee drip few seven term squeeze educate",
+	}
+	mnemonic = "silent toe meat possible chair blossom wait occur this worth option bag nurse find fish scene bench asthma bike wage world quit primary indoor"
+)
+
+func TestCombine(t *testing.T) {
+	for _, order := range [][]string{parts, {parts[2], parts[0], parts[1]}} {
+		result, err := Combine("english", order)
+		assert.Nil(t, err)
+		assert.EqualString(t, mnemonic, result)
+	}
+
+	_, err := Combine("english", parts[:1])
+	assert.Equal(t, ErrInvalidPartCount, err)
+
+	twelve := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	_, err = Combine("english", []string{parts[0], twelve})
+	assert.Equal(t, ErrMismatchedParts, err)
+
+	_, err = Combine("english", []string{parts[0], parts[1][:len(parts[1])-1]})
+	assert.NotNil(t, err)
+}
+
+func TestSplit(t *testing.T) {
+	for _, size := range []int{12, 18, 24} {
+		original, err := bip39.NewRandMnemonic("english", size)
+		assert.Nil(t, err)
+
+		for _, count := range []int{2, 3, 4} {
+			split, err := Split("english", original, count)
+			assert.Nil(t, err)
+			assert.Equal(t, count, len(split))
+
+			for _, part := range split {
+				assert.True(t, bip39.IsMnemonicValid("english", part))
+			}
+
+			result, err := Combine("english", split)
+			assert.Nil(t, err)
+			assert.EqualString(t, original, result)
+		}
+	}
+
+	for _, size := range []int{15, 21} {
+		original, err := bip39.NewRandMnemonic("english", size)
+		assert.Nil(t, err)
+
+		_, err = Split("english", original, 2)
+		assert.Equal(t, ErrInvalidMnemonicSize, err)
+	}
+
+	_, err := Split("english", mnemonic, 1)
+	assert.Equal(t, ErrInvalidPartCount, err)
+}
+
+func TestSplitDeterministic(t *testing.T) {
+	seed := []byte("my dice rolls 3 1 4 1 5 9 2 6")
+
+	split, err := SplitDeterministic("english", mnemonic, 3, seed)
+	assert.Nil(t, err)
+
+	again, err := SplitDeterministic("english", mnemonic, 3, seed)
+	assert.Nil(t, err)
+	assert.EqualStringsSlices(t, split, again)
+
+	result, err := Combine("english", split)
+	assert.Nil(t, err)
+	assert.EqualString(t, mnemonic, result)
+
+	other, err := SplitDeterministic("english", mnemonic, 3, []byte("other dice rolls"))
+	assert.Nil(t, err)
+	assert.False(t, split[0] == other[0])
+
+	// The part count changes every part.
+	four, err := SplitDeterministic("english", mnemonic, 4, seed)
+	assert.Nil(t, err)
+	assert.False(t, split[0] == four[0])
+
+	// The count would be truncated to a byte.
+	_, err = SplitDeterministic("english", mnemonic, 256, seed)
+	assert.Equal(t, ErrTooManyParts, err)
+
+	_, err = SplitDeterministic("english", mnemonic, 255, seed)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,101 @@
+package shamir
+
+import (
+	"crypto/rand"
+
+	"github.com/decen-one/go-bip39"
+)
+
+// headerLength is the length of the threshold, index and identifier at the
+// start of the entropy of a share mnemonic.
+const headerLength = 4
+
+// SplitMnemonic splits the entropy of a mnemonic into count share mnemonics
+// of the same language, any threshold of which recover the mnemonic. The
+// mnemonic must have at most 21 words.
+func SplitMnemonic(lang string, mnemonic string, threshold int, count int) ([]string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(entropy)
+	if len(entropy)+headerLength > 32 {
+		return nil, ErrInvalidSecretLength
+	}
+
+	shares, err := Split(entropy, threshold, count)
+	if err != nil {
+		return nil, err
+	}
+
+	identifier := make([]byte, 2)
+	_, _ = rand.Read(identifier) // err is always nil
+
+	mnemonics := make([]string, len(shares))
+	for i, share := range shares {
+		header := []byte{byte(threshold), share.Index, identifier[0], identifier[1]}
+		data := append(header, share.Value...)
+		mnemonics[i], err = bip39.NewMnemonic(lang, data)
+		wipe(data)
+		wipe(share.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mnemonics, nil
+}
+
+// CombineMnemonics recovers the mnemonic from at least threshold share
+// mnemonics created by SplitMnemonic. Only the first threshold shares are
+// used.
+func CombineMnemonics(lang string, mnemonics []string) (string, error) {
+	var threshold byte
+	var identifier [2]byte
+	shares := make([]Share, 0, len(mnemonics))
+	defer func() {
+		for _, share := range shares {
+			wipe(share.Value)
+		}
+	}()
+
+	for i, mnemonic := range mnemonics {
+		entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+		if err != nil {
+			return "", err
+		}
+		if len(entropy) <= headerLength || entropy[0] == 0 || entropy[1] == 0 {
+			wipe(entropy)
+			return "", ErrInvalidShare
+		}
+
+		if i == 0 {
+			threshold = entropy[0]
+			copy(identifier[:], entropy[2:headerLength])
+		}
+		if entropy[0] != threshold || entropy[2] != identifier[0] || entropy[3] != identifier[1] {
+			wipe(entropy)
+			return "", ErrMismatchedShares
+		}
+
+		shares = append(shares, Share{entropy[1], entropy[headerLength:]})
+	}
+
+	if len(shares) < int(threshold) || len(shares) == 0 {
+		return "", ErrInsufficientShares
+	}
+
+	secret, err := Combine(shares[:threshold])
+	if err != nil {
+		return "", err
+	}
+	defer wipe(secret)
+
+	return bip39.NewMnemonic(lang, secret)
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,189 @@
+// Package shamir implements Shamir's secret sharing over GF(256) and splits
+// the entropy of a mnemonic into shares that are themselves valid BIP39
+// mnemonics.
+//
+// Split and Combine work on bytes like `ssss`: the secret is the value at
+// x = 0 of a random polynomial of degree threshold - 1, evaluated byte by
+// byte with the Rijndael field polynomial x^8 + x^4 + x^3 + x + 1.
+//
+// SplitMnemonic renders every share with NewMnemonic from the entropy
+//
+//	threshold (1 byte) | index (1 byte) | identifier (2 bytes) | share value
+//
+// where threshold is the number of shares needed to recover the secret, index
+// is the x coordinate of the share in [1, 255], identifier is random and
+// common to all shares of one split, and the share value is the share of the
+// entropy of the mnemonic. The header grows the entropy by 4 bytes, so 12,
+// 15, 18 and 21 word mnemonics split into 15, 18, 21 and 24 word shares, and
+// 24 word mnemonics can not be split.
+//
+// This is not the shamir39 format of Ian Coleman's BIP39 tool: shamir39
+// shares start with the word "shamir39-p1" and have no BIP39 checksum, so
+// they can not be ordinary BIP39 phrases.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+)
+
+var (
+	// ErrInvalidThreshold is returned when the threshold is zero or greater
+	// than the share count, or the share count is greater than 255.
+	ErrInvalidThreshold = errors.New("Invalid threshold or share count")
+
+	// ErrDuplicateShares is returned when combining shares with the same
+	// index.
+	ErrDuplicateShares = errors.New("Multiple shares with the same index")
+
+	// ErrMismatchedShares is returned when combining shares that do not
+	// belong to the same split.
+	ErrMismatchedShares = errors.New("Shares do not belong to the same split")
+
+	// ErrInsufficientShares is returned when combining no shares, or fewer
+	// share mnemonics than the threshold they hold.
+	ErrInsufficientShares = errors.New("Insufficient number of shares")
+
+	// ErrInvalidShare is returned when a share has the index 0, or a share
+	// mnemonic is too short to hold a share or has an invalid header.
+	ErrInvalidShare = errors.New("Invalid share")
+
+	// ErrInvalidSecretLength is returned when trying to split a mnemonic whose
+	// shares would not fit in 24 words.
+	ErrInvalidSecretLength = errors.New("Mnemonic must have at most 21 words")
+)
+
+// field holds the powers and logarithms of a generator of GF(256).
+type field struct {
+	exp, log [256]byte
+}
+
+// newField returns the field of the given polynomial, where multiplying by
+// the generator is the given function.
+func newField(polynomial int, multiply func(x int) int) *field {
+	f := &field{}
+	x := 1
+	for i := 0; i < 255; i++ {
+		f.exp[i] = byte(x)
+		f.log[x] = byte(i)
+
+		x = multiply(x)
+		if x&0x100 != 0 {
+			x ^= polynomial
+		}
+	}
+
+	return f
+}
+
+// rijndael is the field of Split and Combine, with the generator 3.
+var rijndael = newField(0x11b, func(x int) int { return x ^ x<<1 })
+
+// Share is the value of the secret polynomial at the x coordinate Index.
+type Share struct {
+	Index byte
+	Value []byte
+}
+
+// Interpolate returns the value at x of the polynomial of lowest degree
+// passing through the given shares using Lagrange interpolation. With fewer
+// shares than the threshold of a split, this is not the polynomial of the
+// split, and the value is wrong.
+func Interpolate(shares []Share, x byte) ([]byte, error) {
+	return rijndael.interpolate(shares, x)
+}
+
+func (f *field) interpolate(shares []Share, x byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrInsufficientShares
+	}
+
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if seen[share.Index] {
+			return nil, ErrDuplicateShares
+		}
+		seen[share.Index] = true
+
+		if len(share.Value) != len(shares[0].Value) {
+			return nil, ErrMismatchedShares
+		}
+	}
+
+	for _, share := range shares {
+		if share.Index == x {
+			return share.Value, nil
+		}
+	}
+
+	// logProduct is the logarithm of the product of (x - x_i) for all i.
+	logProduct := 0
+	for _, share := range shares {
+		logProduct += int(f.log[x^share.Index])
+	}
+
+	result := make([]byte, len(shares[0].Value))
+	for _, share := range shares {
+		// logBasis is the logarithm of the Lagrange basis polynomial of the
+		// share evaluated at x.
+		logBasis := logProduct - int(f.log[x^share.Index])
+		for _, other := range shares {
+			if other.Index != share.Index {
+				logBasis -= int(f.log[share.Index^other.Index])
+			}
+		}
+		logBasis = (logBasis%255 + 255) % 255
+
+		for i, v := range share.Value {
+			if v != 0 {
+				result[i] ^= f.exp[(int(f.log[v])+logBasis)%255]
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Split splits a secret into count shares with the indexes 1 to count, any
+// threshold of which recover the secret.
+func Split(secret []byte, threshold int, count int) ([]Share, error) {
+	if threshold < 1 || threshold > count || count > 255 {
+		return nil, ErrInvalidThreshold
+	}
+
+	return rijndael.split(secret, threshold, count)
+}
+
+func (f *field) split(secret []byte, threshold int, count int) ([]Share, error) {
+	// The secret and threshold - 1 random shares define the polynomial.
+	points := []Share{{0, secret}}
+	for i := 1; i < threshold; i++ {
+		value := make([]byte, len(secret))
+		_, _ = rand.Read(value) // err is always nil
+		points = append(points, Share{byte(i), value})
+	}
+
+	shares := append([]Share{}, points[1:]...)
+	for i := threshold; i <= count; i++ {
+		value, err := f.interpolate(points, byte(i))
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, Share{byte(i), value})
+	}
+
+	return shares, nil
+}
+
+// Combine recovers the secret from at least threshold shares. Shares do not
+// hold the threshold, so fewer shares are not detected and combine to a
+// wrong secret without an error.
+func Combine(shares []Share) ([]byte, error) {
+	for _, share := range shares {
+		if share.Index == 0 {
+			return nil, ErrInvalidShare
+		}
+	}
+
+	return Interpolate(shares, 0)
+}
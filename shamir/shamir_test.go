@@ -0,0 +1,151 @@
+package shamir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	// The line through (1, 1) and (2, 2) is f(x) = x, and 3 * 3 = 5 in GF(256).
+	value, err := Interpolate([]Share{{1, []byte{1, 3}}, {2, []byte{2, 6}}}, 0)
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, []byte{0, 0}, value)
+
+	value, err = Interpolate([]Share{{1, []byte{1, 3}}, {2, []byte{2, 6}}}, 3)
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, []byte{3, 5}, value)
+
+	_, err = Interpolate([]Share{{1, []byte{1}}, {1, []byte{2}}}, 0)
+	assert.Equal(t, ErrDuplicateShares, err)
+
+	_, err = Interpolate([]Share{{1, []byte{1}}, {2, []byte{2, 2}}}, 0)
+	assert.Equal(t, ErrMismatchedShares, err)
+
+	_, err = Interpolate(nil, 0)
+	assert.Equal(t, ErrInsufficientShares, err)
+}
+
+func TestSplit(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+
+	shares, err := Split(secret, 3, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(shares))
+
+	for i := 0; i < 5; i++ {
+		for j := i + 1; j < 5; j++ {
+			for k := j + 1; k < 5; k++ {
+				recovered, err := Combine([]Share{shares[k], shares[i], shares[j]})
+				assert.Nil(t, err)
+				assert.EqualByteSlices(t, secret, recovered)
+			}
+		}
+	}
+
+	shares, err = Split(secret, 1, 2)
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, secret, shares[1].Value)
+
+	for _, invalid := range [][2]int{{0, 1}, {3, 2}, {2, 256}} {
+		_, err = Split(secret, invalid[0], invalid[1])
+		assert.Equal(t, ErrInvalidThreshold, err)
+	}
+
+	_, err = Combine([]Share{{0, secret}})
+	assert.Equal(t, ErrInvalidShare, err)
+}
+
+func TestSplitMnemonic(t *testing.T) {
+	for _, size := range []int{12, 15, 18, 21} {
+		for _, lang := range []string{"english", "japanese"} {
+			mnemonic, err := bip39.NewRandMnemonic(lang, size)
+			assert.Nil(t, err)
+
+			shares, err := SplitMnemonic(lang, mnemonic, 2, 3)
+			assert.Nil(t, err)
+			assert.Equal(t, 3, len(shares))
+
+			// Shares are valid mnemonics, 3 words longer for the header.
+			for _, share := range shares {
+				_, err := bip39.EntropyFromMnemonic(lang, share)
+				assert.Nil(t, err)
+				assert.Equal(t, size+3, len(strings.Fields(share)))
+			}
+
+			for _, subset := range [][]string{shares[:2], {shares[2], shares[0]}, {shares[1], shares[2], shares[0]}} {
+				recovered, err := CombineMnemonics(lang, subset)
+				assert.Nil(t, err)
+				assert.EqualString(t, mnemonic, recovered)
+			}
+
+			_, err = CombineMnemonics(lang, shares[:1])
+			assert.Equal(t, ErrInsufficientShares, err)
+
+			_, err = CombineMnemonics(lang, []string{shares[0], shares[0]})
+			assert.Equal(t, ErrDuplicateShares, err)
+
+			other, err := SplitMnemonic(lang, mnemonic, 2, 3)
+			assert.Nil(t, err)
+			_, err = CombineMnemonics(lang, []string{shares[0], other[1]})
+			assert.Equal(t, ErrMismatchedShares, err)
+		}
+	}
+
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	shares, err := SplitMnemonic("english", mnemonic, 2, 255)
+	assert.Nil(t, err)
+	recovered, err := CombineMnemonics("english", []string{shares[254], shares[31]})
+	assert.Nil(t, err)
+	assert.EqualString(t, mnemonic, recovered)
+
+	for _, invalid := range [][2]int{{0, 2}, {3, 2}, {2, 256}} {
+		_, err = SplitMnemonic("english", mnemonic, invalid[0], invalid[1])
+		assert.Equal(t, ErrInvalidThreshold, err)
+	}
+
+	long, err := bip39.NewRandMnemonic("english", 24)
+	assert.Nil(t, err)
+	_, err = SplitMnemonic("english", long, 2, 3)
+	assert.Equal(t, ErrInvalidSecretLength, err)
+
+	_, err = CombineMnemonics("english", nil)
+	assert.Equal(t, ErrInsufficientShares, err)
+
+	// The entropy of an ordinary mnemonic has no valid header.
+	_, err = CombineMnemonics("english", []string{mnemonic})
+	assert.Equal(t, ErrInvalidShare, err)
+}
+
+func TestCombineMnemonics(t *testing.T) {
+	// The shares were worked out by hand with the threshold 2, the identifier
+	// abcd and the polynomial f(x) = s + x, whose value at x is s XOR x.
+	mnemonic := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	shares := []string{
+		"acoustic ask viable woman tree dish wheat sound lazy view panel woman tree dish wear",
+		"acoustic best viable wife style salt vote quick latin typical gap wife style salt voyage",
+		"acoustic bubble viable wedding sick dilemma vehicle moon ladder token business wedding sick dilemma vendor",
+	}
+	for i := range shares {
+		subset := append(append([]string{}, shares[i:]...), shares[:i]...)
+		recovered, err := CombineMnemonics("english", subset)
+		assert.Nil(t, err)
+		assert.EqualString(t, mnemonic, recovered)
+	}
+
+	_, err := CombineMnemonics("english", shares[2:])
+	assert.Equal(t, ErrInsufficientShares, err)
+
+	// Combine has no threshold, and one share is the constant polynomial.
+	entropy, err := bip39.EntropyFromMnemonic("english", shares[0])
+	assert.Nil(t, err)
+	secret, err := Combine([]Share{{entropy[1], entropy[4:]}})
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, entropy[4:], secret)
+
+	_, err = CombineMnemonics("english", []string{shares[0], strings.Replace(shares[1], "voyage", "wagon", 1)})
+	assert.Equal(t, bip39.ErrChecksumIncorrect, err)
+}
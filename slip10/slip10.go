@@ -0,0 +1,267 @@
+// Package slip10 implements SLIP-0010 hierarchical deterministic key
+// derivation for the ed25519 and NIST P-256 curves on top of BIP39 seeds.
+package slip10
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ripemd160"
+)
+
+// FirstHardenedIndex is the index of the first hardened child key.
+const FirstHardenedIndex = uint32(0x80000000)
+
+var (
+	// ErrInvalidSeed is returned when the seed is not between 128 and 512 bits.
+	ErrInvalidSeed = errors.New("Seed length must be [16, 64] bytes")
+
+	// ErrHardenedOnly is returned when a non-hardened child is requested on a
+	// curve which only supports hardened derivation.
+	ErrHardenedOnly = errors.New("Curve only supports hardened derivation")
+
+	// ErrInvalidPath is returned when a derivation path can not be parsed.
+	ErrInvalidPath = errors.New("Invalid derivation path")
+)
+
+// Curve describes a curve supported by SLIP-0010.
+type Curve struct {
+	name         string
+	seedModifier []byte
+	order        *big.Int
+	hardenedOnly bool
+	publicKey    func(privateKey []byte) []byte
+}
+
+var (
+	// Ed25519 is the ed25519 curve. It only supports hardened derivation.
+	Ed25519 = &Curve{
+		name:         "ed25519",
+		seedModifier: []byte("ed25519 seed"),
+		hardenedOnly: true,
+		publicKey:    ed25519PublicKey,
+	}
+
+	// NIST256p1 is the NIST P-256 curve, also known as secp256r1.
+	NIST256p1 = &Curve{
+		name:         "nist256p1",
+		seedModifier: []byte("Nist256p1 seed"),
+		order:        p256Order,
+		publicKey:    p256PublicKey,
+	}
+
+	p256Order, _ = new(big.Int).SetString("ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551", 16)
+)
+
+// Name returns the SLIP-0010 name of the curve.
+func (c *Curve) Name() string {
+	return c.name
+}
+
+// Key is a SLIP-0010 extended private key.
+type Key struct {
+	Curve             *Curve
+	PrivateKey        []byte
+	ChainCode         []byte
+	Depth             byte
+	ChildNumber       uint32
+	ParentFingerprint uint32
+}
+
+// NewMasterKey creates the master key for the given curve from a seed such as
+// the output of bip39.NewSeed.
+func NewMasterKey(curve *Curve, seed []byte) (*Key, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrInvalidSeed
+	}
+
+	I := hmacSHA512(curve.seedModifier, seed)
+
+	// For curves with an order, retry with I as the data until IL is a valid
+	// private key.
+	for curve.order != nil && !curve.validPrivateKey(I[:32]) {
+		I = hmacSHA512(curve.seedModifier, I)
+	}
+
+	return &Key{
+		Curve:      curve,
+		PrivateKey: I[:32],
+		ChainCode:  I[32:],
+	}, nil
+}
+
+// NewChildKey derives the child key at the given index. Indexes at or above
+// FirstHardenedIndex produce hardened children.
+func (k *Key) NewChildKey(index uint32) (*Key, error) {
+	hardened := index >= FirstHardenedIndex
+	if !hardened && k.Curve.hardenedOnly {
+		return nil, ErrHardenedOnly
+	}
+
+	data := make([]byte, 37)
+	if hardened {
+		copy(data[1:33], k.PrivateKey)
+	} else {
+		copy(data[:33], k.Curve.publicKey(k.PrivateKey))
+	}
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	I := hmacSHA512(k.ChainCode, data)
+
+	child := &Key{
+		Curve:             k.Curve,
+		Depth:             k.Depth + 1,
+		ChildNumber:       index,
+		ParentFingerprint: k.Fingerprint(),
+	}
+
+	if k.Curve.order == nil {
+		child.PrivateKey = I[:32]
+		child.ChainCode = I[32:]
+		return child, nil
+	}
+
+	for {
+		IL := new(big.Int).SetBytes(I[:32])
+		if IL.Cmp(k.Curve.order) < 0 {
+			IL.Add(IL, new(big.Int).SetBytes(k.PrivateKey))
+			IL.Mod(IL, k.Curve.order)
+			if IL.Sign() != 0 {
+				child.PrivateKey = padTo32(IL.Bytes())
+				child.ChainCode = I[32:]
+				return child, nil
+			}
+		}
+
+		// The resulting key is invalid so derive again from IR.
+		data[0] = 1
+		copy(data[1:33], I[32:])
+		I = hmacSHA512(k.ChainCode, data)
+	}
+}
+
+// Derive follows the given path, such as "m/44'/501'/0'", from k.
+func (k *Key) Derive(path string) (*Key, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := k
+	for _, index := range indexes {
+		key, err = key.NewChildKey(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// DeriveForPath creates the master key for the given curve and seed and then
+// follows the given path from it.
+func DeriveForPath(curve *Curve, path string, seed []byte) (*Key, error) {
+	master, err := NewMasterKey(curve, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return master.Derive(path)
+}
+
+// PublicKey returns the 33 byte public key of k. For ed25519 it is the 32
+// byte public key prefixed with a zero byte, otherwise it is the compressed
+// SEC1 encoding of the curve point.
+func (k *Key) PublicKey() []byte {
+	return k.Curve.publicKey(k.PrivateKey)
+}
+
+// Fingerprint returns the first 32 bits of the HASH160 of the public key.
+func (k *Key) Fingerprint() uint32 {
+	sha := sha256.Sum256(k.PublicKey())
+	hasher := ripemd160.New()
+	_, _ = hasher.Write(sha[:])
+
+	return binary.BigEndian.Uint32(hasher.Sum(nil))
+}
+
+// ParsePath parses a derivation path such as "m/0'/1/2h" into child indexes.
+// Hardened indexes may be marked with ', h or H.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, ErrInvalidPath
+	}
+
+	indexes := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		offset := uint32(0)
+		if trimmed := strings.TrimRight(segment, "'hH"); len(trimmed) == len(segment)-1 {
+			segment = trimmed
+			offset = FirstHardenedIndex
+		}
+
+		index, err := strconv.ParseUint(segment, 10, 31)
+		if err != nil {
+			return nil, ErrInvalidPath
+		}
+
+		indexes = append(indexes, uint32(index)+offset)
+	}
+
+	return indexes, nil
+}
+
+// validPrivateKey reports whether key is in the range [1, n-1].
+func (c *Curve) validPrivateKey(key []byte) bool {
+	k := new(big.Int).SetBytes(key)
+	return k.Sign() != 0 && k.Cmp(c.order) < 0
+}
+
+func ed25519PublicKey(privateKey []byte) []byte {
+	publicKey := ed25519.NewKeyFromSeed(privateKey).Public().(ed25519.PublicKey)
+	return append([]byte{0}, publicKey...)
+}
+
+func p256PublicKey(privateKey []byte) []byte {
+	key, err := ecdh.P256().NewPrivateKey(privateKey)
+	if err != nil {
+		// Private keys are always validated against the curve order.
+		panic(err)
+	}
+
+	// Compress the uncompressed 0x04 || X || Y encoding.
+	uncompressed := key.PublicKey().Bytes()
+	compressed := make([]byte, 33)
+	compressed[0] = 2 | uncompressed[64]&1
+	copy(compressed[1:], uncompressed[1:33])
+
+	return compressed
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	_, _ = mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// padTo32 left pads b with zeros to 32 bytes.
+func padTo32(b []byte) []byte {
+	if len(b) >= 32 {
+		return b
+	}
+
+	padded := make([]byte, 32)
+	copy(padded[32-len(b):], b)
+
+	return padded
+}
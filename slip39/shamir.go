@@ -0,0 +1,89 @@
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/decen-one/go-bip39/shamir"
+)
+
+const (
+	// digestIndex and secretIndex are the x coordinates of the digest and
+	// secret shares.
+	digestIndex = 254
+	secretIndex = 255
+
+	digestLength = 4
+)
+
+// splitSecret splits a secret into count shares with the indexes 0 to
+// count - 1, threshold of which are required to recover it.
+func splitSecret(threshold int, count int, secret []byte) ([]shamir.Share, error) {
+	if threshold < 1 || threshold > count || count > MaxShareCount {
+		return nil, ErrInvalidThreshold
+	}
+
+	shares := make([]shamir.Share, 0, count)
+	if threshold == 1 {
+		for i := 0; i < count; i++ {
+			shares = append(shares, shamir.Share{Index: byte(i), Value: secret})
+		}
+		return shares, nil
+	}
+
+	for i := 0; i < threshold-2; i++ {
+		value := make([]byte, len(secret))
+		_, _ = rand.Read(value) // err is always nil
+		shares = append(shares, shamir.Share{Index: byte(i), Value: value})
+	}
+
+	randomPart := make([]byte, len(secret)-digestLength)
+	_, _ = rand.Read(randomPart) // err is always nil
+
+	baseShares := append(shares[:len(shares):len(shares)],
+		shamir.Share{Index: digestIndex, Value: append(digest(randomPart, secret), randomPart...)},
+		shamir.Share{Index: secretIndex, Value: secret},
+	)
+
+	for i := threshold - 2; i < count; i++ {
+		value, err := shamir.Interpolate(baseShares, byte(i))
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, shamir.Share{Index: byte(i), Value: value})
+	}
+
+	return shares, nil
+}
+
+// recoverSecret recovers a secret from threshold shares and verifies its
+// digest.
+func recoverSecret(threshold int, shares []shamir.Share) ([]byte, error) {
+	if threshold == 1 {
+		return shares[0].Value, nil
+	}
+
+	secret, err := shamir.Interpolate(shares, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	digestShare, err := shamir.Interpolate(shares, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(digestShare[:digestLength], digest(digestShare[digestLength:], secret)) {
+		return nil, ErrInvalidDigest
+	}
+
+	return secret, nil
+}
+
+func digest(randomPart []byte, secret []byte) []byte {
+	mac := hmac.New(sha256.New, randomPart)
+	_, _ = mac.Write(secret)
+
+	return mac.Sum(nil)[:digestLength]
+}
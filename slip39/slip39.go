@@ -0,0 +1,474 @@
+// Package slip39 implements SLIP-39 Shamir's secret sharing of a master
+// secret, such as the entropy of a BIP39 mnemonic, into groups of member
+// shares encoded as mnemonics.
+package slip39
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/shamir"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// MinSecretLength is the minimum length in bytes of a master secret.
+	MinSecretLength = 16
+
+	// MaxShareCount is the maximum number of groups and of members in a
+	// group.
+	MaxShareCount = 16
+
+	// DefaultIterationExponent is the iteration exponent used by
+	// SplitMnemonic. The master secret is encrypted with 10000 << e PBKDF2
+	// iterations.
+	DefaultIterationExponent = 1
+
+	radixBits        = 10
+	headerWords      = 4
+	checksumWords    = 3
+	minMnemonicWords = headerWords + checksumWords + (MinSecretLength*8+radixBits-1)/radixBits
+	roundCount       = 4
+	baseIterations   = 10000
+	maxIterationExp  = 15
+	customization    = "shamir"
+	extCustomization = "shamir_extendable"
+	identifierBits   = 15
+	identifierMask   = 1<<identifierBits - 1
+	maxPaddingBits   = 8
+	wordMask         = 1<<radixBits - 1
+)
+
+var (
+	// ErrInvalidWord is returned when a share mnemonic contains a word that
+	// is not in the SLIP-39 wordlist.
+	ErrInvalidWord = errors.New("Invalid SLIP-39 mnemonic word")
+
+	// ErrInvalidMnemonicLength is returned when a share mnemonic has an
+	// invalid number of words.
+	ErrInvalidMnemonicLength = errors.New("Invalid SLIP-39 mnemonic length")
+
+	// ErrChecksumIncorrect is returned when a share mnemonic has the
+	// incorrect checksum.
+	ErrChecksumIncorrect = errors.New("Checksum incorrect")
+
+	// ErrInvalidPadding is returned when the padding bits of a share value
+	// are not zero.
+	ErrInvalidPadding = errors.New("Invalid share value padding")
+
+	// ErrInvalidMasterSecret is returned when trying to split a master
+	// secret that is shorter than 16 bytes or has an odd length.
+	ErrInvalidMasterSecret = errors.New("Master secret must be at least 16 bytes and of even length")
+
+	// ErrInvalidThreshold is returned when a threshold is zero or greater
+	// than its share count, or a share count is greater than 16.
+	ErrInvalidThreshold = errors.New("Invalid threshold or share count")
+
+	// ErrInvalidIterationExponent is returned when the iteration exponent is
+	// outside of [0, 15].
+	ErrInvalidIterationExponent = errors.New("Iteration exponent must be between 0 and 15")
+
+	// ErrInvalidPassphrase is returned when a passphrase contains characters
+	// other than printable ASCII.
+	ErrInvalidPassphrase = errors.New("Passphrase must contain only printable ASCII characters")
+
+	// ErrMismatchedShares is returned when combining shares that do not
+	// belong to the same set.
+	ErrMismatchedShares = errors.New("Shares do not belong to the same set")
+
+	// ErrDuplicateShares is returned when combining distinct shares with the
+	// same index.
+	ErrDuplicateShares = errors.New("Multiple shares with the same index")
+
+	// ErrInsufficientShares is returned when combining fewer or more shares
+	// than the thresholds require.
+	ErrInsufficientShares = errors.New("Wrong number of shares")
+
+	// ErrInvalidDigest is returned when the recovered secret does not match
+	// its digest, usually because shares of different sets were mixed.
+	ErrInvalidDigest = errors.New("Invalid digest of the shared secret")
+)
+
+var (
+	wordIndexes = make(map[string]int, len(wordlist))
+
+	checksumGenerators = [10]uint32{
+		0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+		0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+	}
+)
+
+func init() {
+	for i, word := range wordlist {
+		wordIndexes[word] = i
+	}
+}
+
+// Group is the member threshold and member count of a group of shares.
+type Group struct {
+	MemberThreshold int
+	MemberCount     int
+}
+
+// Share is a single decoded SLIP-39 share.
+type Share struct {
+	// Identifier is the random 15 bit identifier common to all shares of a
+	// master secret.
+	Identifier uint16
+
+	// Extendable reports whether more shares of the same master secret can
+	// be created later with the same identifier.
+	Extendable bool
+
+	IterationExponent int
+	GroupIndex        int
+	GroupThreshold    int
+	GroupCount        int
+	MemberIndex       int
+	MemberThreshold   int
+
+	// Value is the share of the encrypted master secret.
+	Value []byte
+}
+
+// GenerateMnemonics splits a master secret encrypted with passphrase into
+// groups of share mnemonics. Any groupThreshold groups with at least their
+// member threshold of shares each recover the master secret.
+func GenerateMnemonics(groupThreshold int, groups []Group, masterSecret []byte, passphrase string, extendable bool, iterationExponent int) ([][]string, error) {
+	if len(masterSecret) < MinSecretLength || len(masterSecret)%2 != 0 {
+		return nil, ErrInvalidMasterSecret
+	}
+	if iterationExponent < 0 || iterationExponent > maxIterationExp {
+		return nil, ErrInvalidIterationExponent
+	}
+	for _, c := range passphrase {
+		if c < 32 || c > 126 {
+			return nil, ErrInvalidPassphrase
+		}
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) || len(groups) > MaxShareCount {
+		return nil, ErrInvalidThreshold
+	}
+	for _, group := range groups {
+		// A group of several shares with threshold 1 should be a single
+		// share.
+		if group.MemberThreshold == 1 && group.MemberCount > 1 {
+			return nil, ErrInvalidThreshold
+		}
+	}
+
+	var random [2]byte
+	_, _ = rand.Read(random[:]) // err is always nil
+	identifier := binary.BigEndian.Uint16(random[:]) & identifierMask
+
+	encrypted := encrypt(masterSecret, []byte(passphrase), iterationExponent, identifier, extendable)
+
+	groupShares, err := splitSecret(groupThreshold, len(groups), encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonics := make([][]string, len(groups))
+	for i, groupShare := range groupShares {
+		memberShares, err := splitSecret(groups[i].MemberThreshold, groups[i].MemberCount, groupShare.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, memberShare := range memberShares {
+			share := &Share{
+				Identifier:        identifier,
+				Extendable:        extendable,
+				IterationExponent: iterationExponent,
+				GroupIndex:        int(groupShare.Index),
+				GroupThreshold:    groupThreshold,
+				GroupCount:        len(groups),
+				MemberIndex:       int(memberShare.Index),
+				MemberThreshold:   groups[i].MemberThreshold,
+				Value:             memberShare.Value,
+			}
+			mnemonics[i] = append(mnemonics[i], share.Mnemonic())
+		}
+	}
+
+	return mnemonics, nil
+}
+
+// CombineMnemonics recovers the master secret from share mnemonics and the
+// passphrase. Exactly the group threshold of groups, each with exactly its
+// member threshold of shares, must be given.
+func CombineMnemonics(mnemonics []string, passphrase string) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, ErrInsufficientShares
+	}
+
+	var first *Share
+	groups := make(map[int][]*Share)
+	for _, mnemonic := range mnemonics {
+		share, err := ParseShare(mnemonic)
+		if err != nil {
+			return nil, err
+		}
+
+		if first == nil {
+			first = share
+		}
+		if share.Identifier != first.Identifier ||
+			share.Extendable != first.Extendable ||
+			share.IterationExponent != first.IterationExponent ||
+			share.GroupThreshold != first.GroupThreshold ||
+			share.GroupCount != first.GroupCount ||
+			len(share.Value) != len(first.Value) {
+			return nil, ErrMismatchedShares
+		}
+
+		duplicate := false
+		for _, other := range groups[share.GroupIndex] {
+			if other.MemberThreshold != share.MemberThreshold {
+				return nil, ErrMismatchedShares
+			}
+			if other.MemberIndex == share.MemberIndex {
+				if string(other.Value) != string(share.Value) {
+					return nil, ErrDuplicateShares
+				}
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			groups[share.GroupIndex] = append(groups[share.GroupIndex], share)
+		}
+	}
+
+	if len(groups) != first.GroupThreshold {
+		return nil, ErrInsufficientShares
+	}
+
+	groupShares := make([]shamir.Share, 0, len(groups))
+	for index, shares := range groups {
+		if len(shares) != shares[0].MemberThreshold {
+			return nil, ErrInsufficientShares
+		}
+
+		memberShares := make([]shamir.Share, len(shares))
+		for i, share := range shares {
+			memberShares[i] = shamir.Share{Index: byte(share.MemberIndex), Value: share.Value}
+		}
+
+		groupShare, err := recoverSecret(shares[0].MemberThreshold, memberShares)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, shamir.Share{Index: byte(index), Value: groupShare})
+	}
+
+	encrypted, err := recoverSecret(first.GroupThreshold, groupShares)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(encrypted, []byte(passphrase), first.IterationExponent, first.Identifier, first.Extendable), nil
+}
+
+// SplitMnemonic splits the entropy of a BIP39 mnemonic into groups of
+// extendable share mnemonics protected by passphrase. The BIP39 passphrase,
+// if any, is not part of the shares and is still needed to derive the seed.
+func SplitMnemonic(lang string, mnemonic string, passphrase string, groupThreshold int, groups []Group) ([][]string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	return GenerateMnemonics(groupThreshold, groups, entropy, passphrase, true, DefaultIterationExponent)
+}
+
+// RecoverMnemonic combines share mnemonics created by SplitMnemonic back into
+// the BIP39 mnemonic of the given language.
+func RecoverMnemonic(lang string, mnemonics []string, passphrase string) (string, error) {
+	entropy, err := CombineMnemonics(mnemonics, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(lang, entropy)
+}
+
+// ParseShare decodes a share mnemonic, verifying its checksum and padding.
+func ParseShare(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < minMnemonicWords {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	paddingBits := radixBits * (len(words) - headerWords - checksumWords) % 16
+	if paddingBits > maxPaddingBits {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	indexes := make([]int, len(words))
+	for i, word := range words {
+		index, ok := wordIndexes[word]
+		if !ok {
+			return nil, ErrInvalidWord
+		}
+		indexes[i] = index
+	}
+
+	extendable := indexes[1]>>4&1 == 1
+	if polymod(customizationOf(extendable), indexes) != 1 {
+		return nil, ErrChecksumIncorrect
+	}
+
+	header := indexes[2]<<radixBits | indexes[3]
+	share := &Share{
+		Identifier:        uint16(indexes[0]<<5 | indexes[1]>>5),
+		Extendable:        extendable,
+		IterationExponent: indexes[1] & 15,
+		GroupIndex:        header >> 16,
+		GroupThreshold:    header>>12&15 + 1,
+		GroupCount:        header>>8&15 + 1,
+		MemberIndex:       header >> 4 & 15,
+		MemberThreshold:   header&15 + 1,
+	}
+	if share.GroupThreshold > share.GroupCount {
+		return nil, ErrInvalidThreshold
+	}
+
+	value := new(big.Int)
+	for _, index := range indexes[headerWords : len(indexes)-checksumWords] {
+		value.Lsh(value, radixBits)
+		value.Or(value, big.NewInt(int64(index)))
+	}
+
+	length := (radixBits*(len(words)-headerWords-checksumWords) - paddingBits) / 8
+	if value.BitLen() > length*8 {
+		return nil, ErrInvalidPadding
+	}
+	share.Value = value.FillBytes(make([]byte, length))
+
+	return share, nil
+}
+
+// Mnemonic encodes the share as a mnemonic.
+func (s *Share) Mnemonic() string {
+	ext := 0
+	if s.Extendable {
+		ext = 1
+	}
+
+	valueWords := (len(s.Value)*8 + radixBits - 1) / radixBits
+	indexes := make([]int, 0, headerWords+valueWords+checksumWords)
+
+	id := int(s.Identifier)<<5 | ext<<4 | s.IterationExponent
+	header := s.GroupIndex<<16 | (s.GroupThreshold-1)<<12 | (s.GroupCount-1)<<8 | s.MemberIndex<<4 | (s.MemberThreshold - 1)
+	indexes = append(indexes, id>>radixBits, id&wordMask, header>>radixBits, header&wordMask)
+
+	// The value is padded with zero bits on the left to a multiple of 10
+	// bits.
+	value := new(big.Int).SetBytes(s.Value)
+	mask := big.NewInt(wordMask)
+	for i := valueWords - 1; i >= 0; i-- {
+		word := new(big.Int).Rsh(value, uint(i*radixBits))
+		indexes = append(indexes, int(word.And(word, mask).Int64()))
+	}
+
+	checksum := polymod(customizationOf(s.Extendable), append(indexes, 0, 0, 0)) ^ 1
+	for i := checksumWords - 1; i >= 0; i-- {
+		indexes = append(indexes, int(checksum>>(radixBits*uint(i)))&wordMask)
+	}
+
+	words := make([]string, len(indexes))
+	for i, index := range indexes {
+		words[i] = wordlist[index]
+	}
+
+	return strings.Join(words, " ")
+}
+
+func customizationOf(extendable bool) string {
+	if extendable {
+		return extCustomization
+	}
+	return customization
+}
+
+// polymod computes the RS1024 checksum of the customization string followed
+// by the word indexes.
+func polymod(customization string, indexes []int) uint32 {
+	chk := uint32(1)
+	step := func(value uint32) {
+		top := chk >> 20
+		chk = (chk&0xfffff)<<radixBits ^ value
+		for i, generator := range checksumGenerators {
+			if (top>>i)&1 == 1 {
+				chk ^= generator
+			}
+		}
+	}
+
+	for i := 0; i < len(customization); i++ {
+		step(uint32(customization[i]))
+	}
+	for _, index := range indexes {
+		step(uint32(index))
+	}
+
+	return chk
+}
+
+// encrypt encrypts the master secret with a 4 round Feistel network whose
+// round function is PBKDF2-HMAC-SHA256 of the passphrase.
+func encrypt(masterSecret []byte, passphrase []byte, iterationExponent int, identifier uint16, extendable bool) []byte {
+	left := masterSecret[:len(masterSecret)/2]
+	right := masterSecret[len(masterSecret)/2:]
+
+	salt := saltOf(identifier, extendable)
+	for i := 0; i < roundCount; i++ {
+		left, right = right, xor(left, roundFunction(i, passphrase, iterationExponent, salt, right))
+	}
+
+	return append(append([]byte{}, right...), left...)
+}
+
+// decrypt reverses encrypt by running the Feistel rounds in reverse order.
+func decrypt(encrypted []byte, passphrase []byte, iterationExponent int, identifier uint16, extendable bool) []byte {
+	left := encrypted[:len(encrypted)/2]
+	right := encrypted[len(encrypted)/2:]
+
+	salt := saltOf(identifier, extendable)
+	for i := roundCount - 1; i >= 0; i-- {
+		left, right = right, xor(left, roundFunction(i, passphrase, iterationExponent, salt, right))
+	}
+
+	return append(append([]byte{}, right...), left...)
+}
+
+func roundFunction(round int, passphrase []byte, iterationExponent int, salt []byte, data []byte) []byte {
+	password := append([]byte{byte(round)}, passphrase...)
+	iterations := (baseIterations << iterationExponent) / roundCount
+
+	return pbkdf2.Key(password, append(salt[:len(salt):len(salt)], data...), iterations, len(data), sha256.New)
+}
+
+// saltOf returns the salt prefix of the round function. Extendable backups
+// do not bind the encryption to the identifier.
+func saltOf(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+
+	return append([]byte(customization), byte(identifier>>8), byte(identifier))
+}
+
+func xor(a []byte, b []byte) []byte {
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+
+	return result
+}
@@ -0,0 +1,215 @@
+package slip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/decen-one/go-bip39/assert"
+	"github.com/decen-one/go-bip39/bip32"
+)
+
+// vector is a case of testdata/vectors.json, which has the format of
+// vectors.json of https://github.com/trezor/python-shamir-mnemonic: a
+// description, the mnemonics encrypted with the passphrase "TREZOR", the
+// master secret and its BIP32 master key. Invalid sets of mnemonics have an
+// empty master secret.
+type vector struct {
+	description string
+	mnemonics   []string
+	secret      string
+	xprv        string
+}
+
+func (v *vector) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) < 3 || len(fields) > 4 {
+		return fmt.Errorf("vector has %d fields", len(fields))
+	}
+	targets := []interface{}{&v.description, &v.mnemonics, &v.secret, &v.xprv}
+	for i, field := range fields {
+		if err := json.Unmarshal(field, targets[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readVectors(t *testing.T) []vector {
+	data, err := os.ReadFile("testdata/vectors.json")
+	assert.Nil(t, err)
+
+	var vectors []vector
+	assert.Nil(t, json.Unmarshal(data, &vectors))
+	assert.True(t, len(vectors) > 0)
+
+	return vectors
+}
+
+// vectorErrors are the errors expected for the invalid vectors whose
+// description contains the key.
+var vectorErrors = map[string]error{
+	"invalid checksum":              ErrChecksumIncorrect,
+	"invalid padding":               ErrInvalidPadding,
+	"different identifiers":         ErrMismatchedShares,
+	"different iteration exponents": ErrMismatchedShares,
+}
+
+func TestWordlist(t *testing.T) {
+	assert.Equal(t, 1024, len(wordlist))
+
+	// Every word is uniquely identified by its first 4 letters.
+	prefixes := make(map[string]bool)
+	for _, word := range wordlist {
+		prefixes[word[:4]] = true
+	}
+	assert.Equal(t, 1024, len(prefixes))
+}
+
+func TestCombineMnemonics(t *testing.T) {
+	for _, vector := range readVectors(t) {
+		secret, err := CombineMnemonics(vector.mnemonics, "TREZOR")
+		if vector.secret == "" {
+			if err == nil {
+				t.Errorf("%s: no error", vector.description)
+			}
+			for substring, expected := range vectorErrors {
+				if strings.Contains(vector.description, substring) {
+					assert.Equal(t, expected, err)
+				}
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", vector.description, err)
+			continue
+		}
+		assert.EqualString(t, vector.secret, hex.EncodeToString(secret))
+		if vector.xprv != "" {
+			master, err := bip32.NewMasterKey(secret)
+			assert.Nil(t, err)
+			assert.EqualString(t, vector.xprv, master.String())
+		}
+
+		for _, mnemonic := range vector.mnemonics {
+			share, err := ParseShare(mnemonic)
+			assert.Nil(t, err)
+			assert.EqualString(t, mnemonic, share.Mnemonic())
+		}
+	}
+}
+
+func TestGenerateMnemonics(t *testing.T) {
+	secret, _ := hex.DecodeString("bb54aac4b89dc868ba37d9cc21b2cece")
+	groups := []Group{{1, 1}, {2, 3}, {3, 5}}
+
+	for _, extendable := range []bool{false, true} {
+		mnemonics, err := GenerateMnemonics(2, groups, secret, "TREZOR", extendable, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(mnemonics))
+		assert.Equal(t, 5, len(mnemonics[2]))
+
+		for _, shares := range [][]string{
+			{mnemonics[0][0], mnemonics[1][0], mnemonics[1][2]},
+			{mnemonics[1][2], mnemonics[1][1], mnemonics[2][4], mnemonics[2][0], mnemonics[2][1]},
+			{mnemonics[2][3], mnemonics[0][0], mnemonics[2][2], mnemonics[2][1]},
+		} {
+			recovered, err := CombineMnemonics(shares, "TREZOR")
+			assert.Nil(t, err)
+			assert.EqualByteSlices(t, secret, recovered)
+
+			// A wrong passphrase recovers a different secret.
+			recovered, err = CombineMnemonics(shares, "")
+			assert.Nil(t, err)
+			assert.False(t, bytes.Equal(secret, recovered))
+		}
+
+		_, err = CombineMnemonics([]string{mnemonics[0][0], mnemonics[1][0]}, "TREZOR")
+		assert.Equal(t, ErrInsufficientShares, err)
+
+		_, err = CombineMnemonics([]string{mnemonics[0][0], mnemonics[1][0], mnemonics[1][1], mnemonics[1][2]}, "TREZOR")
+		assert.Equal(t, ErrInsufficientShares, err)
+
+		_, err = CombineMnemonics([]string{mnemonics[0][0], mnemonics[1][0], mnemonics[1][0], mnemonics[1][1]}, "TREZOR")
+		assert.Nil(t, err)
+
+		share, err := ParseShare(mnemonics[2][3])
+		assert.Nil(t, err)
+		assert.Equal(t, extendable, share.Extendable)
+		assert.Equal(t, 2, share.GroupIndex)
+		assert.Equal(t, 2, share.GroupThreshold)
+		assert.Equal(t, 3, share.GroupCount)
+		assert.Equal(t, 3, share.MemberIndex)
+		assert.Equal(t, 3, share.MemberThreshold)
+	}
+
+	for _, invalid := range []struct {
+		groupThreshold int
+		groups         []Group
+		secret         []byte
+		passphrase     string
+		exponent       int
+		err            error
+	}{
+		{1, []Group{{1, 1}}, secret[:14], "", 0, ErrInvalidMasterSecret},
+		{1, []Group{{1, 1}}, append(secret, 0), "", 0, ErrInvalidMasterSecret},
+		{1, []Group{{1, 1}}, secret, "", 16, ErrInvalidIterationExponent},
+		{1, []Group{{1, 1}}, secret, "TRÉZOR", 0, ErrInvalidPassphrase},
+		{0, []Group{{1, 1}}, secret, "", 0, ErrInvalidThreshold},
+		{2, []Group{{1, 1}}, secret, "", 0, ErrInvalidThreshold},
+		{1, []Group{{1, 2}}, secret, "", 0, ErrInvalidThreshold},
+		{1, []Group{{3, 2}}, secret, "", 0, ErrInvalidThreshold},
+		{1, []Group{{2, 17}}, secret, "", 0, ErrInvalidThreshold},
+	} {
+		_, err := GenerateMnemonics(invalid.groupThreshold, invalid.groups, invalid.secret, invalid.passphrase, false, invalid.exponent)
+		assert.Equal(t, invalid.err, err)
+	}
+}
+
+func TestSplitMnemonic(t *testing.T) {
+	for _, mnemonic := range []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"void come effort suffer camp survey warrior heavy shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold",
+	} {
+		shares, err := SplitMnemonic("english", mnemonic, "", 1, []Group{{2, 3}})
+		assert.Nil(t, err)
+
+		recovered, err := RecoverMnemonic("english", shares[0][1:], "")
+		assert.Nil(t, err)
+		assert.EqualString(t, mnemonic, recovered)
+	}
+
+	_, err := SplitMnemonic("english", "abandon abandon abandon", "", 1, []Group{{1, 1}})
+	assert.NotNil(t, err)
+}
+
+func TestParseShare(t *testing.T) {
+	mnemonic := readVectors(t)[0].mnemonics[0]
+
+	_, err := ParseShare("duckling enlarge academic academic")
+	assert.Equal(t, ErrInvalidMnemonicLength, err)
+
+	_, err = ParseShare(mnemonic + " academic")
+	assert.Equal(t, ErrInvalidMnemonicLength, err)
+
+	_, err = ParseShare("ducking" + mnemonic[8:])
+	assert.Equal(t, ErrInvalidWord, err)
+
+	share, err := ParseShare("  DUCKLING  " + mnemonic[9:])
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(7945), share.Identifier)
+	assert.False(t, share.Extendable)
+	assert.Equal(t, 0, share.IterationExponent)
+	assert.Equal(t, 1, share.GroupThreshold)
+	assert.Equal(t, 1, share.MemberThreshold)
+	assert.Equal(t, 16, len(share.Value))
+}
@@ -1,11 +1,23 @@
 package bip39
 
 type vector struct {
-	entropy  string
-	mnemonic string
-	seed     string
-	password string
-	lang     string
+	entropy     string
+	mnemonic    string
+	seed        string
+	password    string
+	lang        string
+	fingerprint string
+}
+
+func fingerprintVectors() []vector {
+	return []vector{
+		{
+			mnemonic:    "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			password:    "",
+			lang:        "english",
+			fingerprint: "73c5da0a",
+		},
+	}
 }
 
 func badMnemonicSentences() []vector {
@@ -0,0 +1,129 @@
+package ur
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+)
+
+// Style is a Bytewords encoding style.
+type Style int
+
+const (
+	// Standard separates the full words with spaces.
+	Standard Style = iota
+	// URI separates the full words with dashes.
+	URI
+	// Minimal concatenates the first and last letters of the words, as used
+	// in URs.
+	Minimal
+)
+
+var (
+	// ErrInvalidByteword is returned when decoding a word that is not a
+	// Byteword.
+	ErrInvalidByteword = errors.New("Invalid byteword")
+
+	// ErrChecksumIncorrect is returned when the CRC32 checksum of Bytewords
+	// does not match.
+	ErrChecksumIncorrect = errors.New("Checksum incorrect")
+)
+
+// bytewords is the list of the 256 Bytewords, in the order of the byte values
+// they encode.
+var bytewords = strings.Fields(`
+able acid also apex aqua arch atom aunt away axis back bald barn belt beta bias
+blue body brag brew bulb buzz calm cash cats chef city claw code cola cook cost
+crux curl cusp cyan dark data days deli dice diet door down draw drop drum dull
+duty each easy echo edge epic even exam exit eyes fact fair fern figs film fish
+fizz flap flew flux foxy free frog fuel fund gala game gear gems gift girl glow
+good gray grim guru gush gyro half hang hard hawk heat help high hill holy hope
+horn huts iced idea idle inch inky into iris iron item jade jazz join jolt jowl
+judo jugs jump junk jury keep keno kept keys kick kiln king kite kiwi knob lamb
+lava lazy leaf legs liar limp lion list logo loud love luau luck lung main many
+math maze memo menu meow mild mint miss monk nail navy need news next noon note
+numb obey oboe omit onyx open oval owls paid part peck play plus poem pool pose
+puff puma purr quad quiz race ramp real redo rich road rock roof ruby ruin runs
+rust safe saga scar sets silk skew slot soap solo song stub surf swan taco task
+taxi tent tied time tiny toil tomb toys trip tuna twin ugly undo unit urge user
+vast very veto vial vibe view visa void vows wall wand warm wasp wave waxy webs
+what when whiz wolf work yank yawn yell yoga yurt zaps zero zest zinc zone zoom
+`)
+
+// wordIndexes maps both the full and the minimal form of the Bytewords to
+// their byte values.
+var wordIndexes = map[string]byte{}
+
+func init() {
+	for i, word := range bytewords {
+		wordIndexes[word] = byte(i)
+		wordIndexes[minimalWord(word)] = byte(i)
+	}
+}
+
+func minimalWord(word string) string {
+	return word[:1] + word[3:]
+}
+
+// EncodeBytewords encodes data followed by its CRC32 checksum as Bytewords.
+func EncodeBytewords(style Style, data []byte) string {
+	data = binary.BigEndian.AppendUint32(append([]byte{}, data...), crc32.ChecksumIEEE(data))
+
+	words := make([]string, len(data))
+	for i, b := range data {
+		words[i] = bytewords[b]
+		if style == Minimal {
+			words[i] = minimalWord(words[i])
+		}
+	}
+
+	switch style {
+	case Standard:
+		return strings.Join(words, " ")
+	case URI:
+		return strings.Join(words, "-")
+	default:
+		return strings.Join(words, "")
+	}
+}
+
+// DecodeBytewords decodes Bytewords and verifies their CRC32 checksum. Words
+// are matched case insensitively.
+func DecodeBytewords(style Style, s string) ([]byte, error) {
+	s = strings.ToLower(s)
+
+	var words []string
+	switch style {
+	case Standard:
+		words = strings.Split(s, " ")
+	case URI:
+		words = strings.Split(s, "-")
+	default:
+		if len(s)%2 != 0 {
+			return nil, ErrInvalidByteword
+		}
+		for i := 0; i < len(s); i += 2 {
+			words = append(words, s[i:i+2])
+		}
+	}
+
+	data := make([]byte, len(words))
+	for i, word := range words {
+		index, ok := wordIndexes[word]
+		if !ok || len(word) != 2 && style == Minimal || len(word) != 4 && style != Minimal {
+			return nil, ErrInvalidByteword
+		}
+		data[i] = index
+	}
+
+	if len(data) < 4 {
+		return nil, ErrChecksumIncorrect
+	}
+	data, checksum := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(checksum) {
+		return nil, ErrChecksumIncorrect
+	}
+
+	return data, nil
+}
@@ -0,0 +1,190 @@
+package ur
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// CBOR major types used by the registered types.
+const (
+	majorUnsigned = 0
+	majorBytes    = 2
+	majorText     = 3
+	majorArray    = 4
+	majorMap      = 5
+	majorTag      = 6
+	majorSimple   = 7
+)
+
+// ErrInvalidCBOR is returned when decoding malformed or unexpected CBOR.
+var ErrInvalidCBOR = errors.New("Invalid CBOR")
+
+// appendHeader appends the shortest CBOR head of a major type and argument.
+func appendHeader(b []byte, major byte, value uint64) []byte {
+	major <<= 5
+	switch {
+	case value < 24:
+		return append(b, major|byte(value))
+	case value <= 0xff:
+		return append(b, major|24, byte(value))
+	case value <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(value))
+	case value <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), value)
+	}
+}
+
+func appendBytes(b []byte, data []byte) []byte {
+	return append(appendHeader(b, majorBytes, uint64(len(data))), data...)
+}
+
+func appendText(b []byte, text string) []byte {
+	return append(appendHeader(b, majorText, uint64(len(text))), text...)
+}
+
+// cborReader decodes definite length CBOR items.
+type cborReader struct {
+	data []byte
+}
+
+// header reads the head of the next item.
+func (r *cborReader) header() (byte, uint64, error) {
+	if len(r.data) == 0 {
+		return 0, 0, ErrInvalidCBOR
+	}
+
+	major, info := r.data[0]>>5, r.data[0]&31
+	r.data = r.data[1:]
+	if info < 24 {
+		return major, uint64(info), nil
+	}
+	if info > 27 {
+		// Indefinite lengths and reserved values are not supported.
+		return 0, 0, ErrInvalidCBOR
+	}
+
+	size := 1 << (info - 24)
+	if len(r.data) < size {
+		return 0, 0, ErrInvalidCBOR
+	}
+
+	var value uint64
+	for _, b := range r.data[:size] {
+		value = value<<8 | uint64(b)
+	}
+	r.data = r.data[size:]
+
+	return major, value, nil
+}
+
+// expect reads the head of the next item and checks its major type.
+func (r *cborReader) expect(major byte) (uint64, error) {
+	m, value, err := r.header()
+	if err != nil {
+		return 0, err
+	}
+	if m != major {
+		return 0, ErrInvalidCBOR
+	}
+
+	return value, nil
+}
+
+func (r *cborReader) uint() (uint64, error) {
+	return r.expect(majorUnsigned)
+}
+
+func (r *cborReader) bytes() ([]byte, error) {
+	return r.content(majorBytes)
+}
+
+func (r *cborReader) text() (string, error) {
+	content, err := r.content(majorText)
+	return string(content), err
+}
+
+func (r *cborReader) content(major byte) ([]byte, error) {
+	length, err := r.expect(major)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(r.data)) < length {
+		return nil, ErrInvalidCBOR
+	}
+
+	content := append([]byte{}, r.data[:length]...)
+	r.data = r.data[length:]
+
+	return content, nil
+}
+
+// texts reads an array of text strings.
+func (r *cborReader) texts() ([]string, error) {
+	count, err := r.expect(majorArray)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(len(r.data)) {
+		return nil, ErrInvalidCBOR
+	}
+
+	texts := make([]string, count)
+	for i := range texts {
+		if texts[i], err = r.text(); err != nil {
+			return nil, err
+		}
+	}
+
+	return texts, nil
+}
+
+// date reads a date tagged as days since the epoch.
+func (r *cborReader) date() (time.Time, error) {
+	tag, err := r.expect(majorTag)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if tag != tagDate {
+		return time.Time{}, ErrInvalidCBOR
+	}
+
+	days, err := r.uint()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, 0).UTC().AddDate(0, 0, int(days)), nil
+}
+
+// skip skips the next item, which may be of any type.
+func (r *cborReader) skip() error {
+	major, value, err := r.header()
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case majorBytes, majorText:
+		if uint64(len(r.data)) < value {
+			return ErrInvalidCBOR
+		}
+		r.data = r.data[value:]
+	case majorArray, majorMap:
+		count := value
+		if major == majorMap {
+			count *= 2
+		}
+		for i := uint64(0); i < count; i++ {
+			if err := r.skip(); err != nil {
+				return err
+			}
+		}
+	case majorTag:
+		return r.skip()
+	}
+
+	return nil
+}
@@ -0,0 +1,470 @@
+package ur
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// minFragmentLength is the smallest fragment length chosen by an Encoder.
+const minFragmentLength = 10
+
+// maxMessageLength is the largest message a Decoder accepts. Choosing the
+// fragments of a part takes memory in proportion to the number of fragments,
+// which is bounded by the message length.
+const maxMessageLength = 1 << 16
+
+var (
+	// ErrInvalidFragmentLength is returned when the maximum fragment length
+	// is smaller than the minimum fragment length.
+	ErrInvalidFragmentLength = errors.New("Maximum fragment length must be at least 10")
+
+	// ErrMismatchedPart is returned when a part does not belong to the
+	// message of the parts received before.
+	ErrMismatchedPart = errors.New("Part does not belong to the same message")
+)
+
+// xoshiro256 is the xoshiro256** generator used to choose the fragments of
+// the fountain code.
+type xoshiro256 struct {
+	s [4]uint64
+}
+
+// newXoshiro256 seeds a generator with the SHA256 digest of seed.
+func newXoshiro256(seed []byte) *xoshiro256 {
+	digest := sha256.Sum256(seed)
+
+	x := &xoshiro256{}
+	for i := range x.s {
+		x.s[i] = binary.BigEndian.Uint64(digest[8*i:])
+	}
+
+	return x
+}
+
+func (x *xoshiro256) next() uint64 {
+	s := &x.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+
+	return result
+}
+
+func (x *xoshiro256) nextDouble() float64 {
+	return float64(x.next()) / (1 << 64)
+}
+
+// nextInt returns an integer in [low, high].
+func (x *xoshiro256) nextInt(low int, high int) int {
+	return int(x.nextDouble()*float64(high-low+1)) + low
+}
+
+// chooseDegree samples the number of fragments mixed in a part from the
+// robust soliton like distribution with weights 1/i, with Vose's alias method.
+func chooseDegree(seqLen int, rng *xoshiro256) int {
+	n := seqLen
+	probabilities := make([]float64, n)
+	sum := 0.0
+	for i := range probabilities {
+		probabilities[i] = 1 / float64(i+1)
+		sum += probabilities[i]
+	}
+	for i := range probabilities {
+		probabilities[i] *= float64(n) / sum
+	}
+
+	var small, large []int
+	for i := n - 1; i >= 0; i-- {
+		if probabilities[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	thresholds := make([]float64, n)
+	aliases := make([]int, n)
+	for len(small) > 0 && len(large) > 0 {
+		a, g := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+
+		thresholds[a] = probabilities[a]
+		aliases[a] = g
+		probabilities[g] += probabilities[a] - 1
+		if probabilities[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+	for _, i := range append(large, small...) {
+		thresholds[i] = 1
+	}
+
+	i := int(float64(n) * rng.nextDouble())
+	if rng.nextDouble() < thresholds[i] {
+		return i + 1
+	}
+
+	return aliases[i] + 1
+}
+
+// chooseFragments returns the sorted indexes of the fragments mixed in the
+// part with sequence number seqNum.
+func chooseFragments(seqNum int, seqLen int, checksum uint32) []int {
+	if seqNum <= seqLen {
+		return []int{seqNum - 1}
+	}
+
+	seed := binary.BigEndian.AppendUint32(nil, uint32(seqNum))
+	rng := newXoshiro256(binary.BigEndian.AppendUint32(seed, checksum))
+	degree := chooseDegree(seqLen, rng)
+
+	remaining := make([]int, seqLen)
+	for i := range remaining {
+		remaining[i] = i
+	}
+
+	indexes := make([]int, 0, degree)
+	for len(indexes) < degree {
+		i := rng.nextInt(0, len(remaining)-1)
+		indexes = append(indexes, remaining[i])
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+	sort.Ints(indexes)
+
+	return indexes
+}
+
+// fragmentLength returns the length of the fewest equal fragments of at
+// most maxLength bytes the message can be split into.
+func fragmentLength(messageLength int, maxLength int) int {
+	maxCount := messageLength / minFragmentLength
+	length := messageLength
+	for count := 1; count <= maxCount; count++ {
+		length = (messageLength + count - 1) / count
+		if length <= maxLength {
+			break
+		}
+	}
+
+	return length
+}
+
+// part is a part of a fountain encoded message.
+type part struct {
+	seqNum        int
+	seqLen        int
+	messageLength int
+	checksum      uint32
+	data          []byte
+}
+
+func (p *part) cbor() []byte {
+	b := appendHeader(nil, majorArray, 5)
+	b = appendHeader(b, majorUnsigned, uint64(p.seqNum))
+	b = appendHeader(b, majorUnsigned, uint64(p.seqLen))
+	b = appendHeader(b, majorUnsigned, uint64(p.messageLength))
+	b = appendHeader(b, majorUnsigned, uint64(p.checksum))
+
+	return appendBytes(b, p.data)
+}
+
+func parsePart(data []byte) (*part, error) {
+	r := &cborReader{data: data}
+	if count, err := r.expect(majorArray); err != nil || count != 5 {
+		return nil, ErrInvalidCBOR
+	}
+
+	var values [4]uint64
+	for i := range values {
+		value, err := r.uint()
+		if err != nil || value > 0xffffffff {
+			return nil, ErrInvalidCBOR
+		}
+		values[i] = value
+	}
+
+	fragment, err := r.bytes()
+	if err != nil || len(r.data) != 0 {
+		return nil, ErrInvalidCBOR
+	}
+
+	p := &part{
+		seqNum:        int(values[0]),
+		seqLen:        int(values[1]),
+		messageLength: int(values[2]),
+		checksum:      uint32(values[3]),
+		data:          fragment,
+	}
+	if p.seqNum < 1 || p.messageLength < 1 || p.messageLength > maxMessageLength || len(p.data) == 0 {
+		return nil, ErrInvalidCBOR
+	}
+
+	// The sequence length is untrusted: it must be the number of fragments
+	// of the message.
+	if p.seqLen != (p.messageLength+len(p.data)-1)/len(p.data) {
+		return nil, ErrInvalidCBOR
+	}
+
+	return p, nil
+}
+
+func xor(dst []byte, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// Encoder splits a message into the parts of a multipart UR. The first
+// SeqLen parts hold the fragments in order, the following ones an endless
+// stream of fragments mixed by the fountain code, so a decoder can recover
+// the message from any sufficient set of parts.
+type Encoder struct {
+	urType    string
+	message   []byte
+	checksum  uint32
+	fragments [][]byte
+	seqNum    int
+}
+
+// NewEncoder returns an encoder of the CBOR message of a UR type into parts
+// with fragments of at most maxFragmentLength bytes.
+func NewEncoder(urType string, message []byte, maxFragmentLength int) (*Encoder, error) {
+	if !isValidType(urType) {
+		return nil, ErrInvalidType
+	}
+	if maxFragmentLength < minFragmentLength {
+		return nil, ErrInvalidFragmentLength
+	}
+	if len(message) == 0 {
+		return nil, ErrInvalidCBOR
+	}
+
+	length := fragmentLength(len(message), maxFragmentLength)
+	padded := make([]byte, (len(message)+length-1)/length*length)
+	copy(padded, message)
+
+	e := &Encoder{
+		urType:   urType,
+		message:  message,
+		checksum: crc32.ChecksumIEEE(message),
+	}
+	for i := 0; i < len(padded); i += length {
+		e.fragments = append(e.fragments, padded[i:i+length])
+	}
+
+	return e, nil
+}
+
+// SeqLen returns the number of fragments of the message.
+func (e *Encoder) SeqLen() int {
+	return len(e.fragments)
+}
+
+// NextPart returns the next part. A message with a single fragment is always
+// returned as a single part UR.
+func (e *Encoder) NextPart() string {
+	if len(e.fragments) == 1 {
+		return Encode(e.urType, e.message)
+	}
+
+	e.seqNum++
+	p := &part{
+		seqNum:        e.seqNum,
+		seqLen:        len(e.fragments),
+		messageLength: len(e.message),
+		checksum:      e.checksum,
+		data:          make([]byte, len(e.fragments[0])),
+	}
+	for _, i := range chooseFragments(p.seqNum, p.seqLen, p.checksum) {
+		xor(p.data, e.fragments[i])
+	}
+
+	return "ur:" + e.urType + "/" + strconv.Itoa(p.seqNum) + "-" + strconv.Itoa(p.seqLen) + "/" + EncodeBytewords(Minimal, p.cbor())
+}
+
+// mixedPart is a received part whose data is the XOR of several fragments.
+type mixedPart struct {
+	indexes []int
+	data    []byte
+}
+
+// reduce removes a fragment from a mixed part which contains it.
+func (m *mixedPart) reduce(index int, fragment []byte) bool {
+	i := sort.SearchInts(m.indexes, index)
+	if i == len(m.indexes) || m.indexes[i] != index {
+		return false
+	}
+
+	m.indexes = append(m.indexes[:i:i], m.indexes[i+1:]...)
+	xor(m.data, fragment)
+
+	return true
+}
+
+// Decoder reassembles a message from the parts of a multipart UR, received
+// in any order and with any duplicates. It also accepts a single part UR.
+type Decoder struct {
+	urType    string
+	first     *part
+	seen      map[int]bool
+	fragments map[int][]byte
+	mixed     []*mixedPart
+	message   []byte
+}
+
+// NewDecoder returns an empty decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		seen:      map[int]bool{},
+		fragments: map[int][]byte{},
+	}
+}
+
+// Receive adds a part to the decoder. Parts received after the message is
+// complete are ignored.
+func (d *Decoder) Receive(s string) error {
+	if d.message != nil {
+		return nil
+	}
+
+	urType, components, err := split(s)
+	if err != nil {
+		return err
+	}
+	if d.urType != "" && urType != d.urType {
+		return ErrMismatchedPart
+	}
+
+	if len(components) == 1 {
+		message, err := DecodeBytewords(Minimal, components[0])
+		if err != nil {
+			return err
+		}
+		d.urType, d.message = urType, message
+		return nil
+	}
+
+	sequence := strings.Split(components[0], "-")
+	if len(sequence) != 2 {
+		return ErrInvalidUR
+	}
+	seqNum, err1 := strconv.Atoi(sequence[0])
+	seqLen, err2 := strconv.Atoi(sequence[1])
+	data, err := DecodeBytewords(Minimal, components[1])
+	if err1 != nil || err2 != nil || err != nil {
+		return ErrInvalidUR
+	}
+
+	p, err := parsePart(data)
+	if err != nil {
+		return err
+	}
+	if p.seqNum != seqNum || p.seqLen != seqLen {
+		return ErrInvalidUR
+	}
+
+	if d.first == nil {
+		d.urType, d.first = urType, p
+	} else if p.seqLen != d.first.seqLen || p.messageLength != d.first.messageLength || p.checksum != d.first.checksum || len(p.data) != len(d.first.data) {
+		return ErrMismatchedPart
+	}
+
+	if d.seen[p.seqNum] {
+		return nil
+	}
+	d.seen[p.seqNum] = true
+
+	return d.add(&mixedPart{indexes: chooseFragments(p.seqNum, p.seqLen, p.checksum), data: p.data})
+}
+
+// add reduces a part by the known fragments and propagates any fragment it
+// reveals to the other mixed parts.
+func (d *Decoder) add(m *mixedPart) error {
+	queue := []*mixedPart{m}
+	for len(queue) > 0 {
+		m, queue = queue[0], queue[1:]
+
+		for _, index := range append([]int{}, m.indexes...) {
+			if fragment, ok := d.fragments[index]; ok && len(m.indexes) > 1 {
+				m.reduce(index, fragment)
+			}
+		}
+
+		if len(m.indexes) > 1 {
+			d.mixed = append(d.mixed, m)
+			continue
+		}
+
+		index := m.indexes[0]
+		if _, ok := d.fragments[index]; ok {
+			continue
+		}
+		d.fragments[index] = m.data
+
+		remaining := d.mixed[:0]
+		for _, other := range d.mixed {
+			if other.reduce(index, m.data) && len(other.indexes) == 1 {
+				queue = append(queue, other)
+			} else {
+				remaining = append(remaining, other)
+			}
+		}
+		d.mixed = remaining
+	}
+
+	if len(d.fragments) < d.first.seqLen {
+		return nil
+	}
+
+	message := make([]byte, 0, len(d.first.data)*d.first.seqLen)
+	for i := 0; i < d.first.seqLen; i++ {
+		message = append(message, d.fragments[i]...)
+	}
+	message = message[:d.first.messageLength]
+	if crc32.ChecksumIEEE(message) != d.first.checksum {
+		return ErrChecksumIncorrect
+	}
+	d.message = message
+
+	return nil
+}
+
+// IsComplete reports whether the message has been reassembled.
+func (d *Decoder) IsComplete() bool {
+	return d.message != nil
+}
+
+// Progress returns the fraction of the fragments recovered so far.
+func (d *Decoder) Progress() float64 {
+	if d.message != nil {
+		return 1
+	}
+	if d.first == nil {
+		return 0
+	}
+
+	return float64(len(d.fragments)) / float64(d.first.seqLen)
+}
+
+// Result returns the type and the CBOR message of the reassembled UR.
+func (d *Decoder) Result() (string, []byte, error) {
+	if d.message == nil {
+		return "", nil, ErrIncomplete
+	}
+
+	return d.urType, d.message, nil
+}
@@ -0,0 +1,240 @@
+package ur
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/decen-one/go-bip39"
+)
+
+const (
+	// TypeSeed is the UR type of a seed.
+	TypeSeed = "crypto-seed"
+
+	// TypeBIP39 is the UR type of a BIP39 mnemonic.
+	TypeBIP39 = "crypto-bip39"
+
+	// tagDate is the CBOR tag of a date as days since the epoch (RFC 8943).
+	tagDate = 100
+
+	secondsPerDay = 24 * 60 * 60
+)
+
+// languageCodes maps the languages of the wordlists to the language codes of
+// crypto-bip39.
+var languageCodes = map[string]string{
+	"chinese-simplified":  "zh-Hans",
+	"chinese-traditional": "zh-Hant",
+	"czech":               "cs",
+	"english":             "en",
+	"french":              "fr",
+	"italian":             "it",
+	"japanese":            "ja",
+	"korean":              "ko",
+	"portuguese":          "pt",
+	"spanish":             "es",
+}
+
+var (
+	// ErrUnknownLanguageCode is returned when a crypto-bip39 language code
+	// does not match any wordlist.
+	ErrUnknownLanguageCode = errors.New("Unknown language code")
+
+	// ErrInvalidBirthdate is returned when encoding a seed whose birthdate is
+	// before the epoch, which crypto-seed can not represent.
+	ErrInvalidBirthdate = errors.New("Birthdate must not be before 1970")
+)
+
+// Seed is a crypto-seed, the entropy of a mnemonic with optional metadata.
+type Seed struct {
+	Payload []byte
+
+	// Birthdate is the day the seed was created, if known. Wallets use it to
+	// limit rescans.
+	Birthdate time.Time
+
+	Name string
+	Note string
+}
+
+// NewSeed returns the crypto-seed of a mnemonic.
+func NewSeed(lang string, mnemonic string) (*Seed, error) {
+	entropy, err := bip39.EntropyFromMnemonic(lang, mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Seed{Payload: entropy}, nil
+}
+
+// Mnemonic returns the mnemonic of the seed in a language.
+func (s *Seed) Mnemonic(lang string) (string, error) {
+	return bip39.NewMnemonic(lang, s.Payload)
+}
+
+// CBOR returns the CBOR encoding of the seed.
+func (s *Seed) CBOR() ([]byte, error) {
+	fields := uint64(1)
+	if !s.Birthdate.IsZero() {
+		if s.Birthdate.Unix() < 0 {
+			return nil, ErrInvalidBirthdate
+		}
+		fields++
+	}
+	if s.Name != "" {
+		fields++
+	}
+	if s.Note != "" {
+		fields++
+	}
+
+	b := appendHeader(nil, majorMap, fields)
+	b = appendBytes(appendHeader(b, majorUnsigned, 1), s.Payload)
+	if !s.Birthdate.IsZero() {
+		b = appendHeader(b, majorUnsigned, 2)
+		b = appendHeader(b, majorTag, tagDate)
+		b = appendHeader(b, majorUnsigned, uint64(s.Birthdate.Unix()/secondsPerDay))
+	}
+	if s.Name != "" {
+		b = appendText(appendHeader(b, majorUnsigned, 3), s.Name)
+	}
+	if s.Note != "" {
+		b = appendText(appendHeader(b, majorUnsigned, 4), s.Note)
+	}
+
+	return b, nil
+}
+
+// UR returns the single part crypto-seed UR of the seed.
+func (s *Seed) UR() (string, error) {
+	data, err := s.CBOR()
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(TypeSeed, data), nil
+}
+
+// ParseSeed decodes the CBOR encoding of a crypto-seed. Unknown fields are
+// ignored.
+func ParseSeed(data []byte) (*Seed, error) {
+	r := &cborReader{data: data}
+	fields, err := r.expect(majorMap)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Seed{}
+	for i := uint64(0); i < fields; i++ {
+		key, err := r.uint()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case 1:
+			s.Payload, err = r.bytes()
+		case 2:
+			s.Birthdate, err = r.date()
+		case 3:
+			s.Name, err = r.text()
+		case 4:
+			s.Note, err = r.text()
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(r.data) != 0 || s.Payload == nil {
+		return nil, ErrInvalidCBOR
+	}
+
+	return s, nil
+}
+
+// BIP39 is a crypto-bip39, the words of a mnemonic and their language code.
+type BIP39 struct {
+	Words []string
+	Lang  string
+}
+
+// NewBIP39 returns the crypto-bip39 of a mnemonic.
+func NewBIP39(lang string, mnemonic string) (*BIP39, error) {
+	if _, err := bip39.EntropyFromMnemonic(lang, mnemonic); err != nil {
+		return nil, err
+	}
+
+	return &BIP39{Words: strings.Fields(mnemonic), Lang: languageCodes[strings.ToLower(lang)]}, nil
+}
+
+// Mnemonic returns the mnemonic after checking it against the wordlist of its
+// language code.
+func (b *BIP39) Mnemonic() (string, error) {
+	for lang, code := range languageCodes {
+		if code == b.Lang {
+			mnemonic := strings.Join(b.Words, " ")
+			if _, err := bip39.EntropyFromMnemonic(lang, mnemonic); err != nil {
+				return "", err
+			}
+			return mnemonic, nil
+		}
+	}
+
+	return "", ErrUnknownLanguageCode
+}
+
+// CBOR returns the CBOR encoding of the mnemonic.
+func (b *BIP39) CBOR() []byte {
+	data := appendHeader(nil, majorMap, 2)
+	data = appendHeader(appendHeader(data, majorUnsigned, 1), majorArray, uint64(len(b.Words)))
+	for _, word := range b.Words {
+		data = appendText(data, word)
+	}
+
+	return appendText(appendHeader(data, majorUnsigned, 2), b.Lang)
+}
+
+// UR returns the single part crypto-bip39 UR of the mnemonic.
+func (b *BIP39) UR() string {
+	return Encode(TypeBIP39, b.CBOR())
+}
+
+// ParseBIP39 decodes the CBOR encoding of a crypto-bip39. The language code
+// defaults to English.
+func ParseBIP39(data []byte) (*BIP39, error) {
+	r := &cborReader{data: data}
+	fields, err := r.expect(majorMap)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &BIP39{Lang: "en"}
+	for i := uint64(0); i < fields; i++ {
+		key, err := r.uint()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case 1:
+			b.Words, err = r.texts()
+		case 2:
+			b.Lang, err = r.text()
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(r.data) != 0 || len(b.Words) == 0 {
+		return nil, ErrInvalidCBOR
+	}
+
+	return b, nil
+}
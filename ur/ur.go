@@ -0,0 +1,81 @@
+// Package ur implements Uniform Resources (BCR-2020-005), the Bytewords
+// encoding (BCR-2020-012) and the multipart fountain code used by air-gapped
+// wallets to exchange CBOR data in QR codes, along with the crypto-seed and
+// crypto-bip39 types (BCR-2020-006) for mnemonics.
+package ur
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidUR is returned when decoding a malformed UR.
+	ErrInvalidUR = errors.New("Invalid UR")
+
+	// ErrInvalidType is returned when a UR type is empty or has characters
+	// other than lower case letters, digits and dashes.
+	ErrInvalidType = errors.New("Invalid UR type")
+
+	// ErrIncomplete is returned when asking a Decoder for the message before
+	// it has received enough parts.
+	ErrIncomplete = errors.New("UR is incomplete")
+)
+
+func isValidType(urType string) bool {
+	if urType == "" {
+		return false
+	}
+
+	for _, c := range urType {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Encode returns the single part UR of the CBOR message of a UR type.
+func Encode(urType string, message []byte) string {
+	return "ur:" + urType + "/" + EncodeBytewords(Minimal, message)
+}
+
+// Decode returns the type and the CBOR message of a single part UR. Multipart
+// URs are reassembled with a Decoder.
+func Decode(s string) (string, []byte, error) {
+	urType, components, err := split(s)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(components) != 1 {
+		return "", nil, ErrInvalidUR
+	}
+
+	message, err := DecodeBytewords(Minimal, components[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	return urType, message, nil
+}
+
+// split returns the type and the path components of a UR, which are either
+// the Bytewords of a single part UR or the sequence and the Bytewords of a
+// part. URs are case insensitive as QR codes encode them in upper case.
+func split(s string) (string, []string, error) {
+	s = strings.ToLower(s)
+	if !strings.HasPrefix(s, "ur:") {
+		return "", nil, ErrInvalidUR
+	}
+
+	components := strings.Split(s[len("ur:"):], "/")
+	if len(components) < 2 || len(components) > 3 {
+		return "", nil, ErrInvalidUR
+	}
+	if !isValidType(components[0]) {
+		return "", nil, ErrInvalidType
+	}
+
+	return components[0], components[1:], nil
+}
@@ -0,0 +1,336 @@
+package ur
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/decen-one/go-bip39"
+	"github.com/decen-one/go-bip39/assert"
+)
+
+// Test vectors from BCR-2020-006, BCR-2020-012 and the fountain code tests of
+// the bc-ur reference implementation.
+var (
+	seedUR     = "ur:crypto-seed/oeadgdstaslplabghydrpfmkbggufgludprfgmaotpiecffltnlpqdenos"
+	seedHex    = "c7098580125e2ab0981253468b2dbc52"
+	bip39UR    = "ur:crypto-bip39/oeadlkiyjkisinihjzieihiojpjlkpjoihihjpjlieihihhskthsjeihiejzjliajeiojkhskpjkhsioihieiahsjkisihiojzhsjpihiekthskoihieiajpihktihiyjzhsjnihihiojzjlkoihaoidihjtrkkndede"
+	bip39Words = "shield group erode awake lock sausage cash glare wave crew flame glove"
+
+	// The first parts of a 256 byte message made with makeMessage(256, "Wolf")
+	// and a maximum fragment length of 30.
+	fountainParts = []string{
+		"ur:bytes/1-9/lpadascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtdkgslpgh",
+		"ur:bytes/2-9/lpaoascfadaxcywenbpljkhdcagwdpfnsboxgwlbaawzuefywkdplrsrjynbvygabwjldapfcsgmghhkhstlrdcxaefz",
+		"ur:bytes/3-9/lpaxascfadaxcywenbpljkhdcahelbknlkuejnbadmssfhfrdpsbiegecpasvssovlgeykssjykklronvsjksopdzmol",
+		"ur:bytes/4-9/lpaaascfadaxcywenbpljkhdcasotkhemthydawydtaxneurlkosgwcekonertkbrlwmplssjtammdplolsbrdzcrtas",
+		"ur:bytes/5-9/lpahascfadaxcywenbpljkhdcatbbdfmssrkzmcwnezelennjpfzbgmuktrhtejscktelgfpdlrkfyfwdajldejokbwf",
+		"ur:bytes/6-9/lpamascfadaxcywenbpljkhdcackjlhkhybssklbwefectpfnbbectrljectpavyrolkzczcpkmwidmwoxkilghdsowp",
+		"ur:bytes/7-9/lpatascfadaxcywenbpljkhdcavszmwnjkwtclrtvaynhpahrtoxmwvwatmedibkaegdosftvandiodagdhthtrlnnhy",
+		"ur:bytes/8-9/lpayascfadaxcywenbpljkhdcadmsponkkbbhgsoltjntegepmttmoonftnbuoiyrehfrtsabzsttorodklubbuyaetk",
+		"ur:bytes/9-9/lpasascfadaxcywenbpljkhdcajskecpmdckihdyhphfotjojtfmlnwmadspaxrkytbztpbauotbgtgtaeaevtgavtny",
+		"ur:bytes/10-9/lpbkascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtwdkiplzs",
+		"ur:bytes/11-9/lpbdascfadaxcywenbpljkhdcahelbknlkuejnbadmssfhfrdpsbiegecpasvssovlgeykssjykklronvsjkvetiiapk",
+		"ur:bytes/12-9/lpbnascfadaxcywenbpljkhdcarllaluzmdmgstospeyiefmwejlwtpedamktksrvlcygmzemovovllarodtmtbnptrs",
+	}
+)
+
+// makeMessage returns the pseudorandom message of the reference tests.
+func makeMessage(length int, seed string) []byte {
+	rng := newXoshiro256([]byte(seed))
+	message := make([]byte, length)
+	for i := range message {
+		message[i] = byte(rng.nextInt(0, 255))
+	}
+
+	return message
+}
+
+// bytesMessage wraps a message in a CBOR byte string, the bytes UR type.
+func bytesMessage(message []byte) []byte {
+	return appendBytes(nil, message)
+}
+
+func TestBytewords(t *testing.T) {
+	data := []byte{0, 1, 2, 128, 255}
+	for _, vector := range []struct {
+		style Style
+		s     string
+	}{
+		{Standard, "able acid also lava zoom jade need echo taxi"},
+		{URI, "able-acid-also-lava-zoom-jade-need-echo-taxi"},
+		{Minimal, "aeadaolazmjendeoti"},
+	} {
+		assert.EqualString(t, vector.s, EncodeBytewords(vector.style, data))
+
+		decoded, err := DecodeBytewords(vector.style, vector.s)
+		assert.Nil(t, err)
+		assert.EqualByteSlices(t, data, decoded)
+	}
+
+	decoded, err := DecodeBytewords(Minimal, "AEADAOLAZMJENDEOTI")
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, data, decoded)
+
+	for _, invalid := range []struct {
+		style Style
+		s     string
+		err   error
+	}{
+		{Standard, "able acid also lava zoom jade need echo tacos", ErrInvalidByteword},
+		{Standard, "able acid also lava zoom jade need echo next", ErrChecksumIncorrect},
+		{Minimal, "aeadaolazmjendeot", ErrInvalidByteword},
+		{Minimal, "aeadaolazmjendeotx", ErrInvalidByteword},
+		{Minimal, "aeadao", ErrChecksumIncorrect},
+		{URI, "able acid also lava zoom jade need echo taxi", ErrInvalidByteword},
+	} {
+		_, err := DecodeBytewords(invalid.style, invalid.s)
+		assert.Equal(t, invalid.err, err)
+	}
+}
+
+func TestXoshiro256(t *testing.T) {
+	expected := []uint64{42, 81, 85, 8, 82, 84, 76, 73, 70, 88, 2, 74, 40, 48, 77, 54, 88, 7, 5, 88}
+
+	rng := newXoshiro256([]byte("Wolf"))
+	for _, value := range expected {
+		assert.Equal(t, value, rng.next()%100)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	urType, message, err := Decode(seedUR)
+	assert.Nil(t, err)
+	assert.EqualString(t, TypeSeed, urType)
+
+	seed, err := ParseSeed(message)
+	assert.Nil(t, err)
+	assert.EqualString(t, seedHex, hex.EncodeToString(seed.Payload))
+	assert.EqualString(t, "2020-05-12", seed.Birthdate.Format("2006-01-02"))
+	encoded, err := seed.UR()
+	assert.Nil(t, err)
+	assert.EqualString(t, seedUR, encoded)
+
+	mnemonic, err := seed.Mnemonic("english")
+	assert.Nil(t, err)
+
+	fromMnemonic, err := NewSeed("english", mnemonic)
+	assert.Nil(t, err)
+	fromMnemonic.Birthdate = time.Date(2020, 5, 12, 0, 0, 0, 0, time.UTC)
+	fromMnemonic.Name = "Savings"
+	fromMnemonic.Note = "Kept in the safe"
+
+	data, err := fromMnemonic.CBOR()
+	assert.Nil(t, err)
+	parsed, err := ParseSeed(data)
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, seed.Payload, parsed.Payload)
+	assert.True(t, fromMnemonic.Birthdate.Equal(parsed.Birthdate))
+	assert.EqualString(t, "Savings", parsed.Name)
+	assert.EqualString(t, "Kept in the safe", parsed.Note)
+
+	// Days since the epoch can not be negative.
+	fromMnemonic.Birthdate = time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)
+	_, err = fromMnemonic.CBOR()
+	assert.Equal(t, ErrInvalidBirthdate, err)
+	_, err = fromMnemonic.UR()
+	assert.Equal(t, ErrInvalidBirthdate, err)
+
+	fromMnemonic.Birthdate = time.Unix(0, 0)
+	data, err = fromMnemonic.CBOR()
+	assert.Nil(t, err)
+	parsed, err = ParseSeed(data)
+	assert.Nil(t, err)
+	assert.True(t, fromMnemonic.Birthdate.Equal(parsed.Birthdate))
+
+	// Unknown fields are skipped.
+	extended := append([]byte{0xa3}, message[1:]...)
+	extended = append(extended, 0x18, 0x20, 0x82, 0xf5, 0x61, 'x')
+	parsed, err = ParseSeed(extended)
+	assert.Nil(t, err)
+	assert.EqualByteSlices(t, seed.Payload, parsed.Payload)
+
+	for _, invalid := range [][]byte{
+		{},
+		{0xa0},
+		{0xa1, 0x01, 0x50, 0x00},
+		append(message, 0x00),
+		message[:len(message)-1],
+	} {
+		_, err := ParseSeed(invalid)
+		assert.Equal(t, ErrInvalidCBOR, err)
+	}
+}
+
+func TestBIP39(t *testing.T) {
+	urType, message, err := Decode(bip39UR)
+	assert.Nil(t, err)
+	assert.EqualString(t, TypeBIP39, urType)
+
+	b, err := ParseBIP39(message)
+	assert.Nil(t, err)
+	assert.EqualString(t, "en", b.Lang)
+
+	mnemonic, err := b.Mnemonic()
+	assert.Nil(t, err)
+	assert.EqualString(t, bip39Words, mnemonic)
+
+	b, err = NewBIP39("english", bip39Words)
+	assert.Nil(t, err)
+	assert.EqualString(t, bip39UR, b.UR())
+
+	spanish, err := bip39.NewMnemonic("spanish", make([]byte, 16))
+	assert.Nil(t, err)
+	b, err = NewBIP39("spanish", spanish)
+	assert.Nil(t, err)
+	assert.EqualString(t, "es", b.Lang)
+
+	parsed, err := ParseBIP39(b.CBOR())
+	assert.Nil(t, err)
+	mnemonic, err = parsed.Mnemonic()
+	assert.Nil(t, err)
+	assert.EqualString(t, spanish, mnemonic)
+
+	parsed.Lang = "xx"
+	_, err = parsed.Mnemonic()
+	assert.Equal(t, ErrUnknownLanguageCode, err)
+
+	_, err = NewBIP39("english", "shield group erode awake lock sausage cash glare wave crew flame flame")
+	assert.NotNil(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	urType, message, err := Decode("UR:CRYPTO-SEED/OEADGDSTASLPLABGHYDRPFMKBGGUFGLUDPRFGMAOTPIECFFLTNLPQDENOS")
+	assert.Nil(t, err)
+	assert.EqualString(t, TypeSeed, urType)
+	assert.Equal(t, 25, len(message))
+
+	for _, invalid := range []struct {
+		s   string
+		err error
+	}{
+		{"crypto-seed/oeadgdstaslplabghydrpfmkbggufgludprfgmaotpiecffltnlpqdenos", ErrInvalidUR},
+		{"ur:crypto-seed", ErrInvalidUR},
+		{"ur:crypto_seed/oeadgdstaslplabghydrpfmkbggufgludprfgmaotpiecffltnlpqdenos", ErrInvalidType},
+		{"ur:crypto-seed/oeadgdstaslplabghydrpfmkbggufgludprfgmaotpiecffltnlpqdenoz", ErrInvalidByteword},
+		{"ur:crypto-seed/oeadgdstaslplabghydrpfmkbggufgludprfgmaotpiecffltnlpqdenot", ErrChecksumIncorrect},
+		{fountainParts[0], ErrInvalidUR},
+	} {
+		_, _, err := Decode(invalid.s)
+		assert.Equal(t, invalid.err, err)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	message := bytesMessage(makeMessage(256, "Wolf"))
+
+	e, err := NewEncoder("bytes", message, 30)
+	assert.Nil(t, err)
+	assert.Equal(t, 9, e.SeqLen())
+	for _, expected := range fountainParts {
+		assert.EqualString(t, expected, e.NextPart())
+	}
+
+	e, err = NewEncoder(TypeSeed, bytesMessage(makeMessage(20, "Wolf")), 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, e.SeqLen())
+	assert.True(t, e.NextPart() == e.NextPart())
+
+	_, err = NewEncoder("bytes", message, 9)
+	assert.Equal(t, ErrInvalidFragmentLength, err)
+
+	_, err = NewEncoder("Bytes", message, 30)
+	assert.Equal(t, ErrInvalidType, err)
+}
+
+func TestDecoder(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for _, length := range []int{32, 256, 1000, 4096} {
+		message := bytesMessage(makeMessage(length, "Wolf"))
+
+		e, err := NewEncoder("bytes", message, 100)
+		assert.Nil(t, err)
+
+		// Drop most of the parts to exercise the recovery from mixed parts.
+		d := NewDecoder()
+		for !d.IsComplete() {
+			part := e.NextPart()
+			if e.SeqLen() > 1 && random.Intn(3) != 0 {
+				continue
+			}
+			assert.Nil(t, d.Receive(part))
+		}
+		assert.Equal(t, 1.0, d.Progress())
+
+		urType, result, err := d.Result()
+		assert.Nil(t, err)
+		assert.EqualString(t, "bytes", urType)
+		assert.EqualByteSlices(t, message, result)
+	}
+
+	d := NewDecoder()
+	_, _, err := d.Result()
+	assert.Equal(t, ErrIncomplete, err)
+
+	assert.Nil(t, d.Receive(fountainParts[0]))
+	assert.Nil(t, d.Receive(fountainParts[0]))
+	assert.False(t, d.IsComplete())
+
+	e, err := NewEncoder("bytes", bytesMessage(makeMessage(256, "Other")), 30)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrMismatchedPart, d.Receive(e.NextPart()))
+	assert.Equal(t, ErrMismatchedPart, d.Receive(seedUR))
+	assert.Equal(t, ErrInvalidUR, d.Receive("ur:bytes/1-8/lpadascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtdkgslpgh"))
+}
+
+func TestParsePart(t *testing.T) {
+	valid := &part{seqNum: 9, seqLen: 3, messageLength: 25, checksum: 1, data: make([]byte, 10)}
+	p, err := parsePart(valid.cbor())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, p.seqLen)
+
+	// The sequence length must match the message and fragment lengths, and
+	// the message length is bounded, as the sequence length sets the memory
+	// used to choose the fragments of a part.
+	for _, invalid := range []*part{
+		{seqNum: 9, seqLen: 0xffffffff, messageLength: 25, checksum: 1, data: make([]byte, 10)},
+		{seqNum: 9, seqLen: 2, messageLength: 25, checksum: 1, data: make([]byte, 10)},
+		{seqNum: 9, seqLen: 4, messageLength: 25, checksum: 1, data: make([]byte, 10)},
+		{seqNum: 9, seqLen: 0x10001, messageLength: 0x10001, checksum: 1, data: make([]byte, 1)},
+		{seqNum: 9, seqLen: 1, messageLength: 25, checksum: 1, data: nil},
+		{seqNum: 0, seqLen: 3, messageLength: 25, checksum: 1, data: make([]byte, 10)},
+	} {
+		_, err := parsePart(invalid.cbor())
+		assert.Equal(t, ErrInvalidCBOR, err)
+	}
+}
+
+func TestSeedMultipart(t *testing.T) {
+	seed, err := NewSeed("english", bip39Words)
+	assert.Nil(t, err)
+	seed.Name = "A long name that makes the seed span several parts"
+
+	data, err := seed.CBOR()
+	assert.Nil(t, err)
+	e, err := NewEncoder(TypeSeed, data, 20)
+	assert.Nil(t, err)
+	assert.True(t, e.SeqLen() > 1)
+
+	d := NewDecoder()
+	for !d.IsComplete() {
+		assert.Nil(t, d.Receive(e.NextPart()))
+	}
+
+	urType, message, err := d.Result()
+	assert.Nil(t, err)
+	assert.EqualString(t, TypeSeed, urType)
+
+	parsed, err := ParseSeed(message)
+	assert.Nil(t, err)
+	mnemonic, err := parsed.Mnemonic("english")
+	assert.Nil(t, err)
+	assert.EqualString(t, bip39Words, mnemonic)
+	assert.EqualString(t, seed.Name, parsed.Name)
+}